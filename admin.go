@@ -0,0 +1,296 @@
+package mocha
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/internal/mimetypes"
+)
+
+// _adminPrefix is the path prefix of the admin HTTP API.
+const _adminPrefix = "/__mocha/"
+
+type (
+	// adminHandler serves the admin HTTP API, allowing other processes to manage a running Mocha instance.
+	// Requests outside the admin path prefix are delegated to the next http.Handler.
+	//
+	// Routes:
+	//
+	//	GET    /__mocha/mocks                list all mocks
+	//	POST   /__mocha/mocks                create mocks from JSON or YAML definitions
+	//	DELETE /__mocha/mocks                remove all mocks
+	//	GET    /__mocha/mocks/{id}           get a mock
+	//	DELETE /__mocha/mocks/{id}           remove a mock
+	//	POST   /__mocha/mocks/{id}/enable    enable a mock
+	//	POST   /__mocha/mocks/{id}/disable   disable a mock
+	//	GET    /__mocha/scenarios            list all scenarios and their states
+	//	POST   /__mocha/scenarios/reset      reset all scenarios
+	//	GET    /__mocha/params               list all parameters
+	//	GET    /__mocha/params/{key}         get a parameter
+	//	PUT    /__mocha/params/{key}         set a parameter using the JSON request body
+	//	DELETE /__mocha/params/{key}         remove a parameter
+	adminHandler struct {
+		m    *Mocha
+		next http.Handler
+	}
+
+	// adminMock is the admin HTTP API representation of a Mock.
+	adminMock struct {
+		ID       int                     `json:"id"`
+		Name     string                  `json:"name"`
+		Priority int                     `json:"priority"`
+		Enabled  bool                    `json:"enabled"`
+		Repeat   int                     `json:"repeat"`
+		Hits     int                     `json:"hits"`
+		Scenario *scenarioFileDefinition `json:"scenario,omitempty"`
+	}
+
+	// adminScenario is the admin HTTP API representation of a scenario.
+	adminScenario struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+	}
+
+	// adminError is the admin HTTP API error response body.
+	adminError struct {
+		Error string `json:"error"`
+	}
+)
+
+func newAdminHandler(m *Mocha, next http.Handler) *adminHandler {
+	return &adminHandler{m: m, next: next}
+}
+
+func (h *adminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, _adminPrefix) {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, _adminPrefix), "/"), "/")
+
+	switch segments[0] {
+	case "mocks":
+		h.mocks(w, r, segments[1:])
+	case "scenarios":
+		h.scenarios(w, r, segments[1:])
+	case "params":
+		h.params(w, r, segments[1:])
+	default:
+		adminRespondError(w, http.StatusNotFound, fmt.Errorf("admin route %s not found", r.URL.Path))
+	}
+}
+
+func (h *adminHandler) mocks(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			mocks := h.m.storage.FetchAll()
+			list := make([]adminMock, len(mocks))
+			for i, mock := range mocks {
+				list[i] = toAdminMock(mock)
+			}
+
+			adminRespond(w, http.StatusOK, list)
+		case http.MethodPost:
+			h.createMocks(w, r)
+		case http.MethodDelete:
+			h.m.storage.Flush()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			adminRespondMethodNotAllowed(w, r)
+		}
+
+		return
+	}
+
+	id, err := strconv.Atoi(segments[0])
+	if err != nil {
+		adminRespondError(w, http.StatusBadRequest, fmt.Errorf("invalid mock id %s", segments[0]))
+		return
+	}
+
+	mock := h.findMock(id)
+	if mock == nil {
+		adminRespondError(w, http.StatusNotFound, fmt.Errorf("mock %d not found", id))
+		return
+	}
+
+	if len(segments) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			adminRespond(w, http.StatusOK, toAdminMock(mock))
+		case http.MethodDelete:
+			h.m.storage.Delete(id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			adminRespondMethodNotAllowed(w, r)
+		}
+
+		return
+	}
+
+	if r.Method != http.MethodPost || len(segments) > 2 {
+		adminRespondMethodNotAllowed(w, r)
+		return
+	}
+
+	switch segments[1] {
+	case "enable":
+		mock.Enable()
+	case "disable":
+		mock.Disable()
+	default:
+		adminRespondError(w, http.StatusNotFound, fmt.Errorf("admin route %s not found", r.URL.Path))
+		return
+	}
+
+	adminRespond(w, http.StatusOK, toAdminMock(mock))
+}
+
+func (h *adminHandler) createMocks(w http.ResponseWriter, r *http.Request) {
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		adminRespondError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	definitions, err := decodeMockDefinitions(b, strings.Contains(r.Header.Get(headers.ContentType), "yaml"))
+	if err != nil {
+		adminRespondError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	builders := make([]*MockBuilder, len(definitions))
+	for i, def := range definitions {
+		builders[i], err = mockBuilderFromDefinition(def, "")
+		if err != nil {
+			adminRespondError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	scoped := h.m.AddMocks(builders...)
+	created := make([]adminMock, len(scoped.ListAll()))
+	for i, mock := range scoped.ListAll() {
+		created[i] = toAdminMock(mock)
+	}
+
+	adminRespond(w, http.StatusCreated, created)
+}
+
+func (h *adminHandler) findMock(id int) *Mock {
+	for _, mock := range h.m.storage.FetchAll() {
+		if mock.ID == id {
+			return mock
+		}
+	}
+
+	return nil
+}
+
+func (h *adminHandler) scenarios(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		scenarios := h.m.scenarios.FetchAll()
+		list := make([]adminScenario, len(scenarios))
+		for i, s := range scenarios {
+			list[i] = adminScenario{Name: s.Name, State: s.State}
+		}
+
+		sort.Slice(list, func(a, b int) bool { return list[a].Name < list[b].Name })
+
+		adminRespond(w, http.StatusOK, list)
+	case len(segments) == 1 && segments[0] == "reset" && r.Method == http.MethodPost:
+		h.m.scenarios.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		adminRespondMethodNotAllowed(w, r)
+	}
+}
+
+func (h *adminHandler) params(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 0 {
+		if r.Method != http.MethodGet {
+			adminRespondMethodNotAllowed(w, r)
+			return
+		}
+
+		adminRespond(w, http.StatusOK, h.m.params.GetAll())
+		return
+	}
+
+	if len(segments) > 1 {
+		adminRespondError(w, http.StatusNotFound, fmt.Errorf("admin route %s not found", r.URL.Path))
+		return
+	}
+
+	key := segments[0]
+
+	switch r.Method {
+	case http.MethodGet:
+		value, ok := h.m.params.Get(key)
+		if !ok {
+			adminRespondError(w, http.StatusNotFound, fmt.Errorf("parameter %s not found", key))
+			return
+		}
+
+		adminRespond(w, http.StatusOK, value)
+	case http.MethodPut:
+		var value any
+		if err := json.NewDecoder(r.Body).Decode(&value); err != nil {
+			adminRespondError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		h.m.params.Set(key, value)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		h.m.params.Remove(key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		adminRespondMethodNotAllowed(w, r)
+	}
+}
+
+func toAdminMock(m *Mock) adminMock {
+	am := adminMock{
+		ID:       m.ID,
+		Name:     m.Name,
+		Priority: m.Priority,
+		Enabled:  m.Enabled,
+		Repeat:   m.Repeat,
+		Hits:     m.Hits(),
+	}
+
+	if m.ScenarioName != "" {
+		am.Scenario = &scenarioFileDefinition{
+			Name:          m.ScenarioName,
+			RequiredState: m.ScenarioRequiredState,
+			NewState:      m.ScenarioNewState,
+		}
+	}
+
+	return am
+}
+
+func adminRespond(w http.ResponseWriter, status int, data any) {
+	w.Header().Set(headers.ContentType, mimetypes.JSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func adminRespondError(w http.ResponseWriter, status int, err error) {
+	adminRespond(w, status, adminError{Error: err.Error()})
+}
+
+func adminRespondMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	adminRespondError(w, http.StatusMethodNotAllowed,
+		fmt.Errorf("method %s not allowed on %s", r.Method, r.URL.Path))
+}
@@ -0,0 +1,242 @@
+package mocha
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/testutil"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func TestAdmin(t *testing.T) {
+	m := New(t, Configure().Admin().Build())
+	m.Start()
+
+	existing := m.AddMocks(Get(expect.URLPath("/test")).Name("existing").Reply(reply.OK()))
+	id := existing.ListAll()[0].ID
+
+	decode := func(t *testing.T, res *http.Response, v any) {
+		defer res.Body.Close()
+
+		err := json.NewDecoder(res.Body).Decode(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("should create mocks from json definitions", func(t *testing.T) {
+		res, err := testutil.Post(m.URL()+"/__mocha/mocks", strings.NewReader(`
+			[{"name": "created", "request": {"method": "GET", "url": "/created"}, "response": {"status": 202}}]`)).
+			Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		created := make([]adminMock, 0)
+		decode(t, res, &created)
+
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Len(t, created, 1)
+		assert.Equal(t, "created", created[0].Name)
+
+		res, err = testutil.Get(m.URL() + "/created").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusAccepted, res.StatusCode)
+	})
+
+	t.Run("should create mocks from yaml definitions", func(t *testing.T) {
+		res, err := testutil.Post(m.URL()+"/__mocha/mocks", strings.NewReader("request:\n  url: /yaml\nresponse:\n  status: 201\n")).
+			Header("content-type", "application/yaml").
+			Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+	})
+
+	t.Run("should reject invalid definitions", func(t *testing.T) {
+		res, err := testutil.Post(m.URL()+"/__mocha/mocks", strings.NewReader(`{"request": {"url": {"nope": 1}}}`)).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("should list and get mocks", func(t *testing.T) {
+		res, err := testutil.Get(m.URL() + "/__mocha/mocks").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		list := make([]adminMock, 0)
+		decode(t, res, &list)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Len(t, list, 3)
+
+		res, err = testutil.Get(m.URL() + "/__mocha/mocks/" + strconv.Itoa(id)).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mock := adminMock{}
+		decode(t, res, &mock)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "existing", mock.Name)
+		assert.True(t, mock.Enabled)
+
+		res, err = testutil.Get(m.URL() + "/__mocha/mocks/999999").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("should disable and enable mocks", func(t *testing.T) {
+		res, err := testutil.Post(m.URL()+"/__mocha/mocks/"+strconv.Itoa(id)+"/disable", nil).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.False(t, existing.ListAll()[0].Enabled)
+
+		res, err = testutil.Post(m.URL()+"/__mocha/mocks/"+strconv.Itoa(id)+"/enable", nil).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.True(t, existing.ListAll()[0].Enabled)
+	})
+
+	t.Run("should manage parameters", func(t *testing.T) {
+		res, err := testutil.NewRequest(http.MethodPut, m.URL()+"/__mocha/params/key", strings.NewReader(`{"a": 1}`)).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+		value, ok := m.Parameters().Get("key")
+		assert.True(t, ok)
+		assert.Equal(t, map[string]any{"a": float64(1)}, value)
+
+		res, err = testutil.Get(m.URL() + "/__mocha/params/key").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := make(map[string]any)
+		decode(t, res, &got)
+
+		assert.Equal(t, float64(1), got["a"])
+
+		res, err = testutil.NewRequest(http.MethodDelete, m.URL()+"/__mocha/params/key", nil).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.False(t, m.Parameters().Has("key"))
+	})
+
+	t.Run("should reset scenarios", func(t *testing.T) {
+		m.scenarios.CreateNewIfNeeded("scenario")
+
+		res, err := testutil.Get(m.URL() + "/__mocha/scenarios").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		list := make([]adminScenario, 0)
+		decode(t, res, &list)
+
+		assert.Equal(t, []adminScenario{{Name: "scenario", State: _scenarioStateStarted}}, list)
+
+		res, err = testutil.Post(m.URL()+"/__mocha/scenarios/reset", nil).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.Len(t, m.scenarios.FetchAll(), 0)
+	})
+
+	t.Run("should delete mocks", func(t *testing.T) {
+		res, err := testutil.NewRequest(http.MethodDelete, m.URL()+"/__mocha/mocks/"+strconv.Itoa(id), nil).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.Len(t, m.storage.FetchAll(), 2)
+
+		res, err = testutil.NewRequest(http.MethodDelete, m.URL()+"/__mocha/mocks", nil).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.Len(t, m.storage.FetchAll(), 0)
+	})
+
+	t.Run("should return not found for unknown admin routes", func(t *testing.T) {
+		res, err := testutil.Get(m.URL() + "/__mocha/unknown").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func TestAdmin_Disabled(t *testing.T) {
+	m := New(t)
+	m.Start()
+
+	res, err := testutil.Get(m.URL() + "/__mocha/mocks").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusTeapot, res.StatusCode)
+}
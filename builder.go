@@ -1,9 +1,13 @@
 package mocha
 
 import (
+	"io/fs"
+	"mime"
 	"net/http"
+	"strings"
 
 	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
 	"github.com/vitorsalgado/mocha/v3/params"
 	"github.com/vitorsalgado/mocha/v3/reply"
 )
@@ -28,6 +32,15 @@ func Post(m expect.Matcher) *MockBuilder {
 	return Request().URL(m).Method(http.MethodPost)
 }
 
+// SOAP inits a mock for the SOAP operation with the given name.
+// It matches POST requests whose first element inside the SOAP envelope body has the operation name.
+// Use it along with reply.SOAP. Example:
+//
+//	SOAP("GetUser").URL(expect.URLPath("/users.asmx")).SOAPAction("http://example.com/GetUser")
+func SOAP(operation string) *MockBuilder {
+	return Request().Method(http.MethodPost).Body(expect.SOAPOperation(operation))
+}
+
 // Put inits a mock for Put method.
 func Put(m expect.Matcher) *MockBuilder {
 	return Request().URL(m).Method(http.MethodPut)
@@ -53,6 +66,32 @@ func Options(m expect.Matcher) *MockBuilder {
 	return Request().URL(m).Method(http.MethodOptions)
 }
 
+// WebSocket inits a mock for WebSocket connections.
+// It matches GET requests asking to upgrade to the WebSocket protocol.
+// Use it along with reply.WebSocket to script the conversation.
+func WebSocket(m expect.Matcher) *MockBuilder {
+	return Get(m).Header("Upgrade", expect.ToEqualFold("websocket"))
+}
+
+// Static inits a mock that serves the files from the given fs.FS, like os.DirFS or embed.FS, under the URL path prefix.
+// It matches GET and HEAD requests whose path starts with the prefix, and replies using reply.Static.
+// Example:
+//
+//	Static("/assets/", os.DirFS("testdata/assets"))
+func Static(prefix string, fsys fs.FS) *MockBuilder {
+	b := Request().URL(expect.URLPathPrefix(prefix)).Reply(reply.Static(fsys).Prefix(prefix))
+	b.mock.Expectations = append(
+		b.mock.Expectations,
+		Expectation{
+			Target:        _targetMethod,
+			ValueSelector: func(r *expect.RequestInfo) any { return r.Request.Method },
+			Matcher:       expect.AnyOf(expect.ToEqualFold(http.MethodGet), expect.ToEqualFold(http.MethodHead)),
+			Weight:        _weightNone,
+		})
+
+	return b
+}
+
 // Name defines a name for the mock.
 // Useful to debug.
 func (b *MockBuilder) Name(name string) *MockBuilder {
@@ -72,7 +111,7 @@ func (b *MockBuilder) Method(method string) *MockBuilder {
 	b.mock.Expectations = append(
 		b.mock.Expectations,
 		Expectation{
-			Target:        "method",
+			Target:        _targetMethod,
 			ValueSelector: func(r *expect.RequestInfo) any { return r.Request.Method },
 			Matcher:       expect.ToEqualFold(method),
 			Weight:        _weightNone,
@@ -86,7 +125,7 @@ func (b *MockBuilder) URL(m expect.Matcher) *MockBuilder {
 	b.mock.Expectations = append(
 		b.mock.Expectations,
 		Expectation{
-			Target:        "url",
+			Target:        _targetURL,
 			ValueSelector: func(r *expect.RequestInfo) any { return r.Request.URL },
 			Matcher:       m,
 			Weight:        _weightRegular,
@@ -109,6 +148,22 @@ func (b *MockBuilder) Header(key string, m expect.Matcher) *MockBuilder {
 	return b
 }
 
+// SOAPAction defines the expected SOAP action.
+// It is read from the SOAPAction header, used by SOAP 1.1, or from the action parameter of the Content-Type header,
+// used by SOAP 1.2.
+func (b *MockBuilder) SOAPAction(action string) *MockBuilder {
+	b.mock.Expectations = append(
+		b.mock.Expectations,
+		Expectation{
+			Target:        "header",
+			ValueSelector: func(r *expect.RequestInfo) any { return soapAction(r.Request) },
+			Matcher:       expect.ToEqual(action),
+			Weight:        _weightLow,
+		})
+
+	return b
+}
+
 // Query defines a matcher to a specific query.
 func (b *MockBuilder) Query(key string, m expect.Matcher) *MockBuilder {
 	b.mock.Expectations = append(
@@ -155,6 +210,64 @@ func (b *MockBuilder) FormField(field string, m expect.Matcher) *MockBuilder {
 	return b
 }
 
+// MultipartField defines a matcher for a specific multipart/form-data field by its name.
+// The matcher receives the first field value, as a string.
+// Requests without the field, including non-multipart ones, don't match, and the given matcher is not called for them.
+func (b *MockBuilder) MultipartField(field string, m expect.Matcher) *MockBuilder {
+	b.mock.Expectations = append(b.mock.Expectations,
+		Expectation{
+			Target: "multipart",
+			ValueSelector: func(r *expect.RequestInfo) any {
+				form := multipartForm(r)
+				if form == nil {
+					return nil
+				}
+
+				values, ok := form.Fields[field]
+				if !ok || len(values) == 0 {
+					return nil
+				}
+
+				return values[0]
+			},
+			Matcher: multipartPartMatcher(m),
+			Weight:  _weightVeryLow,
+		})
+
+	return b
+}
+
+// MultipartFile defines a matcher for a file uploaded with a multipart/form-data request, by its field name.
+// The matcher receives the first file as a map with the keys: name, filename, content_type, size and content.
+// Use expect.JSONPath to match each attribute. Example:
+//
+//	MultipartFile("avatar", expect.JSONPath("filename", expect.ToHaveSuffix(".png")))
+//
+// Requests without the file don't match, and the given matcher is not called for them.
+func (b *MockBuilder) MultipartFile(field string, m expect.Matcher) *MockBuilder {
+	b.mock.Expectations = append(b.mock.Expectations,
+		Expectation{
+			Target: "multipart",
+			ValueSelector: func(r *expect.RequestInfo) any {
+				form := multipartForm(r)
+				if form == nil {
+					return nil
+				}
+
+				file := form.File(field)
+				if file == nil {
+					return nil
+				}
+
+				return file.fields()
+			},
+			Matcher: multipartPartMatcher(m),
+			Weight:  _weightVeryLow,
+		})
+
+	return b
+}
+
 // Repeat defines to total times that a mock should be served, if request matches.
 func (b *MockBuilder) Repeat(times int) *MockBuilder {
 	b.mock.Repeat = times
@@ -239,3 +352,17 @@ func (b *MockBuilder) ReplyJust(status int, r ...*reply.StdReply) *MockBuilder {
 func (b *MockBuilder) Build() *Mock {
 	return b.mock
 }
+
+// soapAction returns the SOAP action of the request, without quotes.
+func soapAction(r *http.Request) string {
+	if action := r.Header.Get(headers.SOAPAction); action != "" {
+		return strings.Trim(action, `"`)
+	}
+
+	_, p, err := mime.ParseMediaType(r.Header.Get(headers.ContentType))
+	if err != nil {
+		return ""
+	}
+
+	return p["action"]
+}
@@ -0,0 +1,75 @@
+// Command mocha starts a standalone mock server that serves mocks loaded from definition files.
+// Every flag can also be set using its environment variable counterpart. Flags take precedence.
+//
+// Usage:
+//
+//	mocha -addr :8080 -mocks ./mocks
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/vitorsalgado/mocha/v3"
+)
+
+// notifier logs to the stdout and exits the program when a fatal error happens.
+type notifier struct {
+	mocha.ConsoleNotifier
+}
+
+// FailNow exits the program.
+func (n *notifier) FailNow() {
+	os.Exit(1)
+}
+
+func main() {
+	opts, err := parseOptions(os.Args[1:], os.Getenv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err = run(ctx, opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run starts the mock server and blocks until the given context is done.
+func run(ctx context.Context, opts *options) error {
+	config, err := opts.config(ctx)
+	if err != nil {
+		return err
+	}
+
+	m := mocha.New(&notifier{}, config)
+
+	if opts.mocksDir != "" {
+		scoped, err := m.LoadMocksFromDir(opts.mocksDir)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("loaded %d mocks from %s\n", len(scoped.ListAll()), opts.mocksDir)
+	}
+
+	var info mocha.ServerInfo
+	if opts.tls {
+		info = m.StartTLS()
+	} else {
+		info = m.Start()
+	}
+
+	fmt.Printf("mocha is running on: %s\n", info.URL)
+
+	<-ctx.Done()
+
+	return m.Close()
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/cors"
+)
+
+// options holds the command line configurations.
+type options struct {
+	addr     string
+	mocksDir string
+	logLevel string
+	admin    bool
+
+	recordDir    string
+	recordTarget string
+	recordFormat string
+
+	tls     bool
+	tlsCert string
+	tlsKey  string
+
+	cors                 bool
+	corsAllowedOrigin    string
+	corsAllowedMethods   string
+	corsAllowedHeaders   string
+	corsExposeHeaders    string
+	corsAllowCredentials bool
+	corsMaxAge           int
+}
+
+// parseOptions parses the command line arguments.
+// Environment variables, read using the getenv function, are used as default values.
+func parseOptions(args []string, getenv func(string) string) (*options, error) {
+	opts := &options{}
+	fs := flag.NewFlagSet("mocha", flag.ContinueOnError)
+
+	str := func(p *string, name, env, def, usage string) {
+		if v := getenv(env); v != "" {
+			def = v
+		}
+
+		fs.StringVar(p, name, def, fmt.Sprintf("%s (env: %s)", usage, env))
+	}
+
+	boolean := func(p *bool, name, env string, usage string) {
+		def, _ := strconv.ParseBool(getenv(env))
+		fs.BoolVar(p, name, def, fmt.Sprintf("%s (env: %s)", usage, env))
+	}
+
+	str(&opts.addr, "addr", "MOCHA_ADDR", "", "server address. e.g.: :8080. defaults to a random port on localhost")
+	str(&opts.mocksDir, "mocks", "MOCHA_MOCKS_DIR", "", "directory containing mock definition files")
+	str(&opts.logLevel, "log", "MOCHA_LOG", "verbose", "log verbosity: verbose | silent")
+
+	boolean(&opts.admin, "admin", "MOCHA_ADMIN", "enable the admin HTTP API under /__mocha/")
+
+	str(&opts.recordDir, "record-dir", "MOCHA_RECORD_DIR", "", "directory where recorded mock definition files are written")
+	str(&opts.recordTarget, "record-target", "MOCHA_RECORD_TARGET", "",
+		"URL that receives the requests not matched by any mock. responses are recorded")
+	str(&opts.recordFormat, "record-format", "MOCHA_RECORD_FORMAT", mocha.RecordFormatJSON,
+		"recorded files format: json | yaml")
+
+	boolean(&opts.tls, "tls", "MOCHA_TLS", "start the server with TLS")
+	str(&opts.tlsCert, "tls-cert", "MOCHA_TLS_CERT", "",
+		"TLS certificate file. enables TLS when provided along with -tls-key. a self-signed one is used if empty")
+	str(&opts.tlsKey, "tls-key", "MOCHA_TLS_KEY", "", "TLS key file. enables TLS when provided along with -tls-cert")
+
+	boolean(&opts.cors, "cors", "MOCHA_CORS", "enable CORS")
+	str(&opts.corsAllowedOrigin, "cors-allowed-origin", "MOCHA_CORS_ALLOWED_ORIGIN",
+		cors.ConfigDefault.AllowedOrigin, "CORS allowed origins")
+	str(&opts.corsAllowedMethods, "cors-allowed-methods", "MOCHA_CORS_ALLOWED_METHODS",
+		cors.ConfigDefault.AllowedMethods, "CORS allowed methods")
+	str(&opts.corsAllowedHeaders, "cors-allowed-headers", "MOCHA_CORS_ALLOWED_HEADERS", "", "CORS allowed headers")
+	str(&opts.corsExposeHeaders, "cors-expose-headers", "MOCHA_CORS_EXPOSE_HEADERS", "", "CORS exposed headers")
+	boolean(&opts.corsAllowCredentials, "cors-allow-credentials", "MOCHA_CORS_ALLOW_CREDENTIALS",
+		"CORS allow credentials")
+
+	maxAge, _ := strconv.Atoi(getenv("MOCHA_CORS_MAX_AGE"))
+	fs.IntVar(&opts.corsMaxAge, "cors-max-age", maxAge, "CORS max age (env: MOCHA_CORS_MAX_AGE)")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if (opts.tlsCert == "") != (opts.tlsKey == "") {
+		return nil, fmt.Errorf("both -tls-cert and -tls-key must be provided")
+	}
+
+	if opts.tlsCert != "" {
+		opts.tls = true
+	}
+
+	if opts.recordTarget != "" && opts.recordDir == "" {
+		return nil, fmt.Errorf("-record-dir must be provided when using -record-target")
+	}
+
+	return opts, nil
+}
+
+// config builds the mocha.Config from the command line options.
+func (opts *options) config(ctx context.Context) (mocha.Config, error) {
+	c := mocha.Configure().Context(ctx).Addr(opts.addr)
+
+	switch strings.ToLower(opts.logLevel) {
+	case "verbose":
+		c.LogVerbosity(mocha.LogVerbose)
+	case "silent":
+		c.LogVerbosity(mocha.LogSilently)
+	default:
+		return mocha.Config{}, fmt.Errorf("invalid log verbosity %s. use: verbose | silent", opts.logLevel)
+	}
+
+	if opts.admin {
+		c.Admin()
+	}
+
+	if opts.recordDir != "" {
+		c.Record(mocha.RecordConfig{Dir: opts.recordDir, Target: opts.recordTarget, Format: opts.recordFormat})
+	}
+
+	if opts.tlsCert != "" {
+		cert, err := tls.LoadX509KeyPair(opts.tlsCert, opts.tlsKey)
+		if err != nil {
+			return mocha.Config{}, err
+		}
+
+		c.TLS(&tls.Config{Certificates: []tls.Certificate{cert}})
+	}
+
+	if opts.cors {
+		c.CORS(cors.Config{
+			AllowedOrigin:     opts.corsAllowedOrigin,
+			AllowCredentials:  opts.corsAllowCredentials,
+			AllowedMethods:    opts.corsAllowedMethods,
+			AllowedHeaders:    opts.corsAllowedHeaders,
+			ExposeHeaders:     opts.corsExposeHeaders,
+			MaxAge:            opts.corsMaxAge,
+			SuccessStatusCode: cors.ConfigDefault.SuccessStatusCode,
+		})
+	}
+
+	return c.Build(), nil
+}
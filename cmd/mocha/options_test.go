@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/cors"
+)
+
+func TestParseOptions(t *testing.T) {
+	t.Run("should use environment variables as default values", func(t *testing.T) {
+		env := map[string]string{
+			"MOCHA_ADDR":         ":8080",
+			"MOCHA_MOCKS_DIR":    "mocks",
+			"MOCHA_LOG":          "silent",
+			"MOCHA_CORS":         "true",
+			"MOCHA_CORS_MAX_AGE": "10",
+		}
+
+		opts, err := parseOptions(nil, func(k string) string { return env[k] })
+
+		assert.Nil(t, err)
+		assert.Equal(t, ":8080", opts.addr)
+		assert.Equal(t, "mocks", opts.mocksDir)
+		assert.Equal(t, "silent", opts.logLevel)
+		assert.True(t, opts.cors)
+		assert.Equal(t, 10, opts.corsMaxAge)
+		assert.Equal(t, cors.ConfigDefault.AllowedOrigin, opts.corsAllowedOrigin)
+	})
+
+	t.Run("should give precedence to flags", func(t *testing.T) {
+		env := map[string]string{"MOCHA_ADDR": ":8080"}
+
+		opts, err := parseOptions([]string{"-addr", ":3000", "-tls", "-cors-allowed-origin", "example.org"},
+			func(k string) string { return env[k] })
+
+		assert.Nil(t, err)
+		assert.Equal(t, ":3000", opts.addr)
+		assert.True(t, opts.tls)
+		assert.Equal(t, "example.org", opts.corsAllowedOrigin)
+	})
+
+	t.Run("should fail when only one TLS file is provided", func(t *testing.T) {
+		_, err := parseOptions([]string{"-tls-cert", "cert.pem"}, func(string) string { return "" })
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should enable TLS when the certificate and key files are provided", func(t *testing.T) {
+		opts, err := parseOptions([]string{"-tls-cert", "cert.pem", "-tls-key", "key.pem"}, func(string) string { return "" })
+
+		assert.Nil(t, err)
+		assert.True(t, opts.tls)
+	})
+
+	t.Run("should fail when record target is provided without record dir", func(t *testing.T) {
+		_, err := parseOptions([]string{"-record-target", "http://example.org"}, func(string) string { return "" })
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should configure recording", func(t *testing.T) {
+		env := map[string]string{"MOCHA_RECORD_DIR": "recorded"}
+
+		opts, err := parseOptions([]string{"-record-target", "http://example.org", "-record-format", "yaml"},
+			func(k string) string { return env[k] })
+		assert.Nil(t, err)
+
+		config, err := opts.config(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, mocha.RecordConfig{Dir: "recorded", Target: "http://example.org", Format: "yaml"}, config.Record)
+	})
+
+	t.Run("should fail with invalid log verbosity", func(t *testing.T) {
+		opts, err := parseOptions([]string{"-log", "none"}, func(string) string { return "" })
+		assert.Nil(t, err)
+
+		_, err = opts.config(context.Background())
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should build mocha config", func(t *testing.T) {
+		opts, err := parseOptions([]string{"-log", "silent", "-cors", "-admin"}, func(string) string { return "" })
+		assert.Nil(t, err)
+
+		config, err := opts.config(context.Background())
+		assert.Nil(t, err)
+		assert.True(t, config.Admin)
+		assert.Equal(t, mocha.LogSilently, config.LogVerbosity)
+		assert.Equal(t, cors.ConfigDefault.AllowedMethods, config.CORS.AllowedMethods)
+	})
+}
+
+func TestRun(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := l.Addr().String()
+	l.Close()
+
+	opts, err := parseOptions(
+		[]string{"-addr", addr, "-mocks", "../../_testdata/mocks", "-log", "silent"},
+		func(string) string { return "" })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() { done <- run(ctx, opts) }()
+
+	var res *http.Response
+	for i := 0; i < 50; i++ {
+		res, err = http.Get("http://" + addr + "/scenario/1")
+		if err == nil {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+	cancel()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Nil(t, <-done)
+}
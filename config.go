@@ -2,9 +2,11 @@ package mocha
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 
 	"github.com/vitorsalgado/mocha/v3/cors"
+	"github.com/vitorsalgado/mocha/v3/reply"
 )
 
 type LogVerbosity int
@@ -37,10 +39,41 @@ type (
 		// Server defines a custom mock HTTP server.
 		Server Server
 
+		// TLS defines a custom TLS configuration, used when the mock server is started with StartTLS.
+		// If no certificates are provided, the built-in server uses a self-signed one.
+		TLS *tls.Config
+
 		// LogVerbosity defines the level of logs
 		LogVerbosity LogVerbosity
 
-		corsEnabled bool
+		// JournalSize defines the maximum number of received requests retained by the request journal.
+		// Once the limit is reached, the oldest requests are discarded. Defaults to 1000.
+		JournalSize int
+
+		// Record defines how request and response pairs are recorded as mock definition files.
+		// Use Configurer.Record to enable it.
+		Record RecordConfig
+
+		// NotMatchedReply defines the reply served when a request doesn't match any mock.
+		// The OnRequestNotMatched event is still emitted.
+		// Defaults to a plain-text mismatch report with the status http.StatusTeapot.
+		NotMatchedReply reply.Reply
+
+		// ErrorReply defines the reply served when an error occurs while serving a request.
+		// The OnError event is still emitted.
+		// Defaults to a plain-text error description with the status http.StatusTeapot.
+		ErrorReply reply.Reply
+
+		// Strict makes the test fail, when the mock server is closed by CloseOnCleanup, for every request that
+		// did not match any mock or failed to be served. Each failure is reported with its mismatch details.
+		Strict bool
+
+		// Admin enables the admin HTTP API, served under the path prefix "/__mocha/".
+		// It allows managing mocks, scenarios and parameters from other processes.
+		Admin bool
+
+		corsEnabled   bool
+		recordEnabled bool
 	}
 
 	// Configurer is Config builder,
@@ -105,6 +138,12 @@ func (cb *Configurer) Server(srv Server) *Configurer {
 	return cb
 }
 
+// TLS sets a custom TLS configuration for the mock HTTP server.
+func (cb *Configurer) TLS(config *tls.Config) *Configurer {
+	cb.conf.TLS = config
+	return cb
+}
+
 // LogVerbosity configure the verbosity of informative logs.
 // Defaults to LogVerbose.
 func (cb *Configurer) LogVerbosity(l LogVerbosity) *Configurer {
@@ -112,6 +151,47 @@ func (cb *Configurer) LogVerbosity(l LogVerbosity) *Configurer {
 	return cb
 }
 
+// JournalSize sets the maximum number of received requests retained by the request journal.
+func (cb *Configurer) JournalSize(size int) *Configurer {
+	cb.conf.JournalSize = size
+	return cb
+}
+
+// Record enables recording request and response pairs as mock definition files.
+// See RecordConfig for more details.
+func (cb *Configurer) Record(config RecordConfig) *Configurer {
+	cb.conf.Record = config
+	cb.conf.recordEnabled = true
+	return cb
+}
+
+// NotMatchedReply sets the reply served when a request doesn't match any mock.
+// Use it to respond with a custom status, like http.StatusNotFound, or to forward the request
+// to a real server with reply.From, mocking only part of an API.
+func (cb *Configurer) NotMatchedReply(rep reply.Reply) *Configurer {
+	cb.conf.NotMatchedReply = rep
+	return cb
+}
+
+// ErrorReply sets the reply served when an error occurs while serving a request.
+func (cb *Configurer) ErrorReply(rep reply.Reply) *Configurer {
+	cb.conf.ErrorReply = rep
+	return cb
+}
+
+// Strict enables the strict mode.
+// Requests that don't match any mock, or fail to be served, will fail the test on CloseOnCleanup.
+func (cb *Configurer) Strict() *Configurer {
+	cb.conf.Strict = true
+	return cb
+}
+
+// Admin enables the admin HTTP API, served under the path prefix "/__mocha/".
+func (cb *Configurer) Admin() *Configurer {
+	cb.conf.Admin = true
+	return cb
+}
+
 // Build builds a new Config with previously configured values.
 func (cb *Configurer) Build() Config {
 	return cb.conf
@@ -35,6 +35,19 @@ type (
 
 		// Matches is the function that does the actual matching logic.
 		Matches func(v any, args Args) (bool, error)
+
+		// Literal describes the literal value expected by simple equality and prefix matchers.
+		// It is optional metadata, used to index mocks by method and URL path. It never replaces Matches.
+		Literal *Literal
+	}
+
+	// Literal holds the literal string a Matcher compares values with.
+	Literal struct {
+		// Value is the expected string.
+		Value string
+
+		// Prefix indicates that values are expected to start with Value, instead of being equal to it.
+		Prefix bool
 	}
 )
 
@@ -14,6 +14,11 @@ import (
 func ToEqual(expected any) Matcher {
 	matcher := Matcher{}
 	matcher.Name = "Equal"
+
+	if str, ok := expected.(string); ok {
+		matcher.Literal = &Literal{Value: str}
+	}
+
 	matcher.DescribeMismatch = func(p string, v any) string {
 		return fmt.Sprintf("%s\n%s",
 			fmt.Sprintf("expected: %v", colorize.Green(misc.Stringify(expected))),
@@ -60,6 +65,7 @@ func ToEqualJSON(expected any) Matcher {
 func ToEqualFold(expected string) Matcher {
 	m := Matcher{}
 	m.Name = "EqualFold"
+	m.Literal = &Literal{Value: expected}
 	m.DescribeMismatch = func(p string, v any) string {
 		return fmt.Sprintf("%s\n%s",
 			fmt.Sprintf("expected: %v", colorize.Green(expected)),
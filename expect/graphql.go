@@ -0,0 +1,196 @@
+package expect
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/vitorsalgado/mocha/v3/internal/graphqlx"
+	"github.com/vitorsalgado/mocha/v3/internal/jsonx"
+)
+
+// graphQLRequest holds the standard GraphQL request parameters.
+type graphQLRequest struct {
+	query         string
+	operationName string
+	variables     any
+}
+
+// GraphQLOperation matches GraphQL requests by their operation name.
+// It uses the "operationName" request parameter and, when it is absent, the name of the single operation in the query.
+// Requests are read from the parsed JSON body or, for GET requests, from the URL query.
+// Use it with MockBuilder.Body. Example:
+//
+//	mocha.Post(expect.URLPath("/graphql")).Body(expect.GraphQLOperation("GetUser"))
+func GraphQLOperation(name string) Matcher {
+	m := Matcher{}
+	m.Name = "GraphQLOperation"
+	m.DescribeMismatch = func(p string, v any) string {
+		return fmt.Sprintf("graphql operation is not %s", name)
+	}
+	m.Matches = func(v any, args Args) (bool, error) {
+		op, ok := graphQLOperation(v, args)
+		if !ok {
+			return false, nil
+		}
+
+		return op.Name == name, nil
+	}
+
+	return m
+}
+
+// GraphQLOperationType matches GraphQL requests by their operation type: query, mutation or subscription.
+func GraphQLOperationType(operationType string) Matcher {
+	m := Matcher{}
+	m.Name = "GraphQLOperationType"
+	m.DescribeMismatch = func(p string, v any) string {
+		return fmt.Sprintf("graphql operation type is not %s", operationType)
+	}
+	m.Matches = func(v any, args Args) (bool, error) {
+		op, ok := graphQLOperation(v, args)
+		if !ok {
+			return false, nil
+		}
+
+		return op.Type == operationType, nil
+	}
+
+	return m
+}
+
+// GraphQLField matches GraphQL requests whose operation selects the field in the given dot separated path.
+// Fragments are resolved and aliases are accepted as path segments.
+// Example:
+//
+//	Query: query { user(id: 1) { name address { city } } }
+//	GraphQLField("user.address.city") will return true
+func GraphQLField(path string) Matcher {
+	m := Matcher{}
+	m.Name = "GraphQLField"
+	m.DescribeMismatch = func(p string, v any) string {
+		return fmt.Sprintf("graphql operation does not select the field %s", path)
+	}
+	m.Matches = func(v any, args Args) (bool, error) {
+		op, ok := graphQLOperation(v, args)
+		if !ok {
+			return false, nil
+		}
+
+		return op.HasField(path), nil
+	}
+
+	return m
+}
+
+// GraphQLVariables applies the provided matcher to the GraphQL variable value in the given path.
+// An empty path applies the matcher to all variables.
+// Example:
+//
+//	Variables: { "id": 1, "filter": { "status": "open" } }
+//	GraphQLVariables("filter.status", ToEqual("open")) will return true
+func GraphQLVariables(path string, matcher Matcher) Matcher {
+	m := Matcher{}
+	m.Name = "GraphQLVariables"
+	m.DescribeMismatch = func(p string, v any) string {
+		return fmt.Sprintf("matcher %s applied on graphql variable %s did not match", matcher.Name, path)
+	}
+	m.Matches = func(v any, args Args) (bool, error) {
+		req, ok := readGraphQLRequest(v, args)
+		if !ok || req.variables == nil {
+			return false, nil
+		}
+
+		value := req.variables
+
+		if path != "" {
+			var err error
+			value, err = jsonx.Reach(path, req.variables)
+			if err != nil || value == nil {
+				return false, nil
+			}
+		}
+
+		return matcher.Matches(value, args)
+	}
+
+	return m
+}
+
+// GraphQLQuery applies the provided matcher to the GraphQL query text.
+func GraphQLQuery(matcher Matcher) Matcher {
+	m := Matcher{}
+	m.Name = "GraphQLQuery"
+	m.DescribeMismatch = func(p string, v any) string {
+		return fmt.Sprintf("matcher %s applied on graphql query did not match", matcher.Name)
+	}
+	m.Matches = func(v any, args Args) (bool, error) {
+		req, ok := readGraphQLRequest(v, args)
+		if !ok {
+			return false, nil
+		}
+
+		return matcher.Matches(req.query, args)
+	}
+
+	return m
+}
+
+// graphQLOperation parses the request query and returns the operation that will be executed.
+func graphQLOperation(v any, args Args) (*graphqlx.Operation, bool) {
+	req, ok := readGraphQLRequest(v, args)
+	if !ok {
+		return nil, false
+	}
+
+	doc, err := graphqlx.Parse(req.query)
+	if err != nil {
+		return nil, false
+	}
+
+	op, err := doc.Operation(req.operationName)
+	if err != nil {
+		return nil, false
+	}
+
+	return op, true
+}
+
+// readGraphQLRequest reads the GraphQL request parameters from the parsed JSON body.
+// When there is no body, they are read from the URL query, as GET requests send them.
+func readGraphQLRequest(v any, args Args) (*graphQLRequest, bool) {
+	if body, ok := v.(map[string]any); ok {
+		query, ok := body["query"].(string)
+		if !ok {
+			return nil, false
+		}
+
+		name, _ := body["operationName"].(string)
+
+		return &graphQLRequest{query: query, operationName: name, variables: body["variables"]}, true
+	}
+
+	if v != nil || args.RequestInfo == nil || args.RequestInfo.Request == nil {
+		return nil, false
+	}
+
+	r := args.RequestInfo.Request
+	if r.Method != http.MethodGet {
+		return nil, false
+	}
+
+	q := r.URL.Query()
+	if !q.Has("query") {
+		return nil, false
+	}
+
+	req := &graphQLRequest{query: q.Get("query"), operationName: q.Get("operationName")}
+
+	if raw := q.Get("variables"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req.variables); err != nil {
+			return nil, false
+		}
+	}
+
+	return req, true
+}
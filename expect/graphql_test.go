@@ -0,0 +1,91 @@
+package expect
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQL(t *testing.T) {
+	t.Parallel()
+
+	body := map[string]any{
+		"query": `query GetUser($id: ID!) { user(id: $id) { name address { city } } }
+			mutation DeleteUser($id: ID!) { deleteUser(id: $id) }`,
+		"operationName": "GetUser",
+		"variables":     map[string]any{"id": "10", "filter": map[string]any{"status": "open"}},
+	}
+
+	testCases := []struct {
+		name     string
+		matcher  Matcher
+		expected bool
+	}{
+		{"operation", GraphQLOperation("GetUser"), true},
+		{"other operation", GraphQLOperation("DeleteUser"), false},
+		{"operation type", GraphQLOperationType("query"), true},
+		{"other operation type", GraphQLOperationType("mutation"), false},
+		{"field", GraphQLField("user.address.city"), true},
+		{"field from other operation", GraphQLField("deleteUser"), false},
+		{"variable", GraphQLVariables("id", ToEqual("10")), true},
+		{"nested variable", GraphQLVariables("filter.status", ToEqual("open")), true},
+		{"missing variable", GraphQLVariables("name", ToEqual("open")), false},
+		{"all variables", GraphQLVariables("", ToHaveKey("filter")), true},
+		{"query", GraphQLQuery(ToContain("GetUser")), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.matcher.Matches(body, emptyArgs())
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestGraphQL_OperationFromQuery(t *testing.T) {
+	t.Parallel()
+
+	body := map[string]any{"query": `mutation CreateUser { createUser(name: "dev") { id } }`}
+
+	res, err := GraphQLOperation("CreateUser").Matches(body, emptyArgs())
+	assert.Nil(t, err)
+	assert.True(t, res)
+
+	res, err = GraphQLOperationType("mutation").Matches(body, emptyArgs())
+	assert.Nil(t, err)
+	assert.True(t, res)
+}
+
+func TestGraphQL_GetRequest(t *testing.T) {
+	t.Parallel()
+
+	q := url.Values{}
+	q.Set("query", "query Search { search { id } }")
+	q.Set("variables", `{"term": "mocha"}`)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost/graphql?"+q.Encode(), nil)
+	args := Args{RequestInfo: &RequestInfo{Request: req}}
+
+	res, err := GraphQLOperation("Search").Matches(nil, args)
+	assert.Nil(t, err)
+	assert.True(t, res)
+
+	res, err = GraphQLVariables("term", ToEqual("mocha")).Matches(nil, args)
+	assert.Nil(t, err)
+	assert.True(t, res)
+}
+
+func TestGraphQL_NotGraphQL(t *testing.T) {
+	t.Parallel()
+
+	values := []any{nil, "text", map[string]any{"name": "dev"}, map[string]any{"query": "invalid {"}}
+
+	for _, v := range values {
+		res, err := GraphQLOperation("Any").Matches(v, emptyArgs())
+		assert.Nil(t, err)
+		assert.False(t, res)
+	}
+}
@@ -9,6 +9,7 @@ import (
 func ToHavePrefix(prefix string) Matcher {
 	m := Matcher{}
 	m.Name = "HasPrefix"
+	m.Literal = &Literal{Value: prefix, Prefix: true}
 	m.DescribeMismatch = func(p string, v any) string {
 		return fmt.Sprintf("value %v, doest not have the prefix %s", v, prefix)
 	}
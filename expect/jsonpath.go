@@ -7,9 +7,12 @@ import (
 )
 
 // JSONPath applies the provided matcher to the JSON field value in the given path.
+// Paths support wildcards, recursive descent, filters, slices and quoted keys. Paths that can select multiple
+// values, like "items[*].id", pass the list of matches to the matcher.
 // Example:
 //
 //	JSONPath("address.city", EqualTo("Santiago"))
+//	JSONPath("items[?(@.status == 'open')]", ToHaveLen(2))
 func JSONPath(p string, matcher Matcher) Matcher {
 	m := Matcher{}
 	m.Name = "JSONPath"
@@ -27,3 +30,73 @@ func JSONPath(p string, matcher Matcher) Matcher {
 
 	return m
 }
+
+// JSONPathAll applies the provided matcher to every value selected by the JSON path.
+// It returns false when the path doesn't select any value.
+// Example:
+//
+//	JSONPathAll("items[*].price", ToBePresent())
+func JSONPathAll(p string, matcher Matcher) Matcher {
+	path, compileErr := jsonx.Compile(p)
+
+	m := Matcher{}
+	m.Name = "JSONPathAll"
+	m.DescribeMismatch = func(_ string, v any) string {
+		return fmt.Sprintf("matcher %s did not match all values selected by json path %s", matcher.Name, p)
+	}
+	m.Matches = func(v any, args Args) (bool, error) {
+		if compileErr != nil {
+			return false, compileErr
+		}
+
+		nodes := path.Find(v)
+		if len(nodes) == 0 {
+			return false, nil
+		}
+
+		for _, node := range nodes {
+			res, err := matcher.Matches(node, args)
+			if err != nil || !res {
+				return false, err
+			}
+		}
+
+		return true, nil
+	}
+
+	return m
+}
+
+// JSONPathAny returns true when the provided matcher matches any value selected by the JSON path.
+// Example:
+//
+//	JSONPathAny("$..tags[*]", ToEqual("urgent"))
+func JSONPathAny(p string, matcher Matcher) Matcher {
+	path, compileErr := jsonx.Compile(p)
+
+	m := Matcher{}
+	m.Name = "JSONPathAny"
+	m.DescribeMismatch = func(_ string, v any) string {
+		return fmt.Sprintf("matcher %s did not match any value selected by json path %s", matcher.Name, p)
+	}
+	m.Matches = func(v any, args Args) (bool, error) {
+		if compileErr != nil {
+			return false, compileErr
+		}
+
+		for _, node := range path.Find(v) {
+			res, err := matcher.Matches(node, args)
+			if err != nil {
+				return false, err
+			}
+
+			if res {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+
+	return m
+}
@@ -35,9 +35,79 @@ func TestJSONPath(t *testing.T) {
 		assert.True(t, res)
 	})
 
+	t.Run("should match member names that are not valid json path names", func(t *testing.T) {
+		data := map[string]any{"@type": "Person", "$ref": "#/user", "first name": "dev"}
+
+		for path, expected := range map[string]string{"@type": "Person", "$ref": "#/user", "first name": "dev"} {
+			res, err := JSONPath(path, ToEqual(expected)).Matches(data, emptyArgs())
+			assert.Nil(t, err)
+			assert.True(t, res)
+		}
+	})
+
 	t.Run("should return error when any error occurs", func(t *testing.T) {
 		res, err := JSONPath("312nj.,", ToEqual("anything")).Matches(m, emptyArgs())
 		assert.NotNil(t, err)
 		assert.False(t, res)
 	})
+	t.Run("should pass the list of matches for paths with multiple values", func(t *testing.T) {
+		data := map[string]any{"items": []any{
+			map[string]any{"id": 1, "status": "open"},
+			map[string]any{"id": 2, "status": "closed"},
+			map[string]any{"id": 3, "status": "open"},
+		}}
+
+		res, err := JSONPath("items[?(@.status == 'open')].id", ToEqual([]any{1, 3})).Matches(data, emptyArgs())
+		assert.Nil(t, err)
+		assert.True(t, res)
+
+		res, err = JSONPath("items[?(@.status == 'draft')]", ToHaveLen(0)).Matches(data, emptyArgs())
+		assert.Nil(t, err)
+		assert.True(t, res)
+	})
+}
+
+func TestJSONPathAllAndAny(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"order": map[string]any{
+			"items": []any{
+				map[string]any{"sku": "a1", "price": 10, "tags": []any{"new"}},
+				map[string]any{"sku": "b2", "price": 25, "tags": []any{"sale", "urgent"}},
+			},
+		},
+		"key.with.dots": "ok",
+	}
+
+	testCases := []struct {
+		name     string
+		matcher  Matcher
+		expected bool
+	}{
+		{"all", JSONPathAll("order.items[*].sku", ToHaveLen(2)), true},
+		{"all mismatch", JSONPathAll("$..price", ToEqual(10)), false},
+		{"all without matches", JSONPathAll("order.items[*].missing", ToBePresent()), false},
+		{"any", JSONPathAny("$..tags[*]", ToEqual("urgent")), true},
+		{"any mismatch", JSONPathAny("$..tags[*]", ToEqual("old")), false},
+		{"any with filter", JSONPathAny("order.items[?(@.price > 20)].sku", ToEqual("b2")), true},
+		{"any with slice", JSONPathAny("order.items[-1:].sku", ToEqual("a1")), false},
+		{"quoted keys", JSONPathAll("['key.with.dots']", ToEqual("ok")), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.matcher.Matches(data, emptyArgs())
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+
+	t.Run("should return error with invalid paths", func(t *testing.T) {
+		_, err := JSONPathAll("items[", ToBePresent()).Matches(data, emptyArgs())
+		assert.NotNil(t, err)
+
+		_, err = JSONPathAny("items[", ToBePresent()).Matches(data, emptyArgs())
+		assert.NotNil(t, err)
+	})
 }
@@ -0,0 +1,103 @@
+package expect
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vitorsalgado/mocha/v3/internal/jsonschema"
+)
+
+// ToMatchJSONSchema returns true when the matcher argument is valid against the given JSON Schema draft 2020-12
+// document. Use it with the parsed JSON body or with values selected by JSONPath to assert their shape.
+// References are resolved within the schema document, and the format keyword is not validated.
+// Example:
+//
+//	ToMatchJSONSchema(`{"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}`)
+func ToMatchJSONSchema(schema string) Matcher {
+	s, err := jsonschema.Compile([]byte(schema))
+	return jsonSchemaMatcher(s, err)
+}
+
+// ToMatchJSONSchemaFile works like ToMatchJSONSchema, loading the JSON Schema document from the given file.
+func ToMatchJSONSchemaFile(filename string) Matcher {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return jsonSchemaMatcher(nil, err)
+	}
+
+	s, err := jsonschema.Compile(b)
+
+	return jsonSchemaMatcher(s, err)
+}
+
+func jsonSchemaMatcher(schema *jsonschema.Schema, compileErr error) Matcher {
+	validate := func(v any) ([]jsonschema.Violation, bool) {
+		value, ok := jsonValue(v)
+		if !ok {
+			return nil, false
+		}
+
+		return schema.Validate(value), true
+	}
+
+	m := Matcher{}
+	m.Name = "ToMatchJSONSchema"
+	m.DescribeMismatch = func(p string, v any) string {
+		if compileErr != nil {
+			return fmt.Sprintf("invalid json schema: %v", compileErr)
+		}
+
+		violations, ok := validate(v)
+		if !ok {
+			return "value is not valid json"
+		}
+
+		descriptions := make([]string, len(violations))
+		for i, violation := range violations {
+			descriptions[i] = violation.String()
+		}
+
+		return fmt.Sprintf("value does not match the json schema: %s", strings.Join(descriptions, "; "))
+	}
+	m.Matches = func(v any, args Args) (bool, error) {
+		if compileErr != nil {
+			return false, compileErr
+		}
+
+		violations, ok := validate(v)
+
+		return ok && len(violations) == 0, nil
+	}
+
+	return m
+}
+
+// jsonValue converts the value to the types produced by json.Unmarshal, encoding and decoding it when necessary.
+// A []byte is decoded as a JSON document.
+func jsonValue(v any) (any, bool) {
+	switch e := v.(type) {
+	case nil, bool, float64, string:
+		return v, true
+	case []byte:
+		var value any
+		if err := json.Unmarshal(e, &value); err != nil {
+			return nil, false
+		}
+
+		return value, true
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+
+	var value any
+	if err = json.Unmarshal(b, &value); err != nil {
+		return nil, false
+	}
+
+	return value, true
+}
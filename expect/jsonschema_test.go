@@ -0,0 +1,98 @@
+package expect
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const _userSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["id", "name"],
+	"properties": {
+		"id": {"type": "integer"},
+		"name": {"type": "string"},
+		"tags": {"type": "array", "items": {"type": "string"}}
+	}
+}`
+
+func TestToMatchJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	var body any
+	_ = json.Unmarshal([]byte(`{"id": 1, "name": "dev", "tags": ["a"]}`), &body)
+
+	testCases := []struct {
+		name     string
+		value    any
+		expected bool
+	}{
+		{"parsed json", body, true},
+		{"raw json", []byte(`{"id": 1, "name": "dev"}`), true},
+		{"go values", map[string]any{"id": 1, "name": "dev", "tags": []string{"a"}}, true},
+		{"structs", struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		}{1, "dev"}, true},
+		{"invalid", map[string]any{"id": "1", "tags": []any{1}}, false},
+		{"not json", []byte(`{`), false},
+		{"nil", nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := ToMatchJSONSchema(_userSchema).Matches(tc.value, emptyArgs())
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+
+	t.Run("should apply to values selected by JSONPath", func(t *testing.T) {
+		res, err := JSONPath("tags", ToMatchJSONSchema(`{"type": "array", "minItems": 1}`)).Matches(body, emptyArgs())
+		assert.Nil(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("should describe each violation", func(t *testing.T) {
+		m := ToMatchJSONSchema(_userSchema)
+		v := map[string]any{"id": "1", "tags": []any{1}}
+
+		res, err := m.Matches(v, emptyArgs())
+		assert.Nil(t, err)
+		assert.False(t, res)
+		assert.Equal(t,
+			`value does not match the json schema: #: missing required property "name"; `+
+				`#/id: expected type integer, got string; #/tags/0: expected type string, got integer`,
+			m.DescribeMismatch("body", v))
+	})
+
+	t.Run("should return error with invalid schemas", func(t *testing.T) {
+		res, err := ToMatchJSONSchema(`{"type": 1}`).Matches(body, emptyArgs())
+		assert.NotNil(t, err)
+		assert.False(t, res)
+	})
+}
+
+func TestToMatchJSONSchemaFile(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "user.json")
+	if err := os.WriteFile(filename, []byte(_userSchema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := ToMatchJSONSchemaFile(filename).Matches(map[string]any{"id": 1, "name": "dev"}, emptyArgs())
+	assert.Nil(t, err)
+	assert.True(t, res)
+
+	res, err = ToMatchJSONSchemaFile(filename).Matches(map[string]any{"id": 1}, emptyArgs())
+	assert.Nil(t, err)
+	assert.False(t, res)
+
+	_, err = ToMatchJSONSchemaFile(filepath.Join(t.TempDir(), "missing.json")).Matches(nil, emptyArgs())
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,55 @@
+package expect
+
+import (
+	"fmt"
+
+	"github.com/vitorsalgado/mocha/v3/internal/xmlx"
+)
+
+// SOAPOperation returns true when the first element inside the SOAP envelope body has the given local name.
+// It works with SOAP 1.1 and 1.2 envelopes. Example:
+//
+//	XML: <soap:Envelope><soap:Body><GetUser><id>1</id></GetUser></soap:Body></soap:Envelope>
+//	SOAPOperation("GetUser") will return true
+func SOAPOperation(name string) Matcher {
+	m := Matcher{}
+	m.Name = "SOAPOperation"
+	m.DescribeMismatch = func(p string, v any) string {
+		return fmt.Sprintf("soap operation is not %s", name)
+	}
+	m.Matches = func(v any, args Args) (bool, error) {
+		doc, ok := xmlDocument(v)
+		if !ok {
+			return false, nil
+		}
+
+		op := soapOperation(doc)
+
+		return op != nil && op.Name.Local == name, nil
+	}
+
+	return m
+}
+
+// soapOperation returns the first element inside the envelope body.
+func soapOperation(doc *xmlx.Node) *xmlx.Node {
+	envelope := doc.Root()
+	if envelope == nil || envelope.Name.Local != "Envelope" {
+		return nil
+	}
+
+	for _, el := range envelope.Elements() {
+		if el.Name.Local != "Body" {
+			continue
+		}
+
+		elements := el.Elements()
+		if len(elements) == 0 {
+			return nil
+		}
+
+		return elements[0]
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package expect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSOAPOperation(t *testing.T) {
+	t.Parallel()
+
+	envelope := `<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope">
+		<soap:Header><Session>abc</Session></soap:Header>
+		<soap:Body><m:GetUser xmlns:m="urn:users"><id>1</id></m:GetUser></soap:Body>
+	</soap:Envelope>`
+
+	testCases := []struct {
+		name     string
+		value    any
+		op       string
+		expected bool
+	}{
+		{"operation", envelope, "GetUser", true},
+		{"other operation", envelope, "DeleteUser", false},
+		{"header element", envelope, "Session", false},
+		{"not an envelope", `<GetUser><id>1</id></GetUser>`, "GetUser", false},
+		{"empty body", `<Envelope><Body></Body></Envelope>`, "GetUser", false},
+		{"not xml", "text", "GetUser", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := SOAPOperation(tc.op).Matches(tc.value, emptyArgs())
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
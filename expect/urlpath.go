@@ -10,27 +10,57 @@ import (
 func URLPath(expected string) Matcher {
 	m := Matcher{}
 	m.Name = "URLPath"
+	m.Literal = &Literal{Value: expected}
 	m.DescribeMismatch = func(p string, v any) string {
 		return fmt.Sprintf("url does not have the expected path %s", expected)
 	}
 	m.Matches = func(v any, params Args) (bool, error) {
-		switch e := v.(type) {
-		case *url.URL:
-			return strings.EqualFold(expected, e.Path), nil
-		case url.URL:
-			return strings.EqualFold(expected, e.Path), nil
-		case string:
-			u, err := url.Parse(e)
-			if err != nil {
-				return false, err
-			}
-
-			return strings.EqualFold(expected, u.Path), nil
-
-		default:
-			panic("URLPath matcher only accepts the types: *url.URL | url.URL | string")
+		p, err := urlPath("URLPath", v)
+		if err != nil {
+			return false, err
 		}
+
+		return strings.EqualFold(expected, p), nil
+	}
+
+	return m
+}
+
+// URLPathPrefix returns true if request URL path starts with the given prefix, ignoring case.
+func URLPathPrefix(prefix string) Matcher {
+	m := Matcher{}
+	m.Name = "URLPathPrefix"
+	m.Literal = &Literal{Value: prefix, Prefix: true}
+	m.DescribeMismatch = func(p string, v any) string {
+		return fmt.Sprintf("url path does not have the prefix %s", prefix)
+	}
+	m.Matches = func(v any, params Args) (bool, error) {
+		p, err := urlPath("URLPathPrefix", v)
+		if err != nil {
+			return false, err
+		}
+
+		return len(p) >= len(prefix) && strings.EqualFold(prefix, p[:len(prefix)]), nil
 	}
 
 	return m
 }
+
+func urlPath(matcher string, v any) (string, error) {
+	switch e := v.(type) {
+	case *url.URL:
+		return e.Path, nil
+	case url.URL:
+		return e.Path, nil
+	case string:
+		u, err := url.Parse(e)
+		if err != nil {
+			return "", err
+		}
+
+		return u.Path, nil
+
+	default:
+		panic(matcher + " matcher only accepts the types: *url.URL | url.URL | string")
+	}
+}
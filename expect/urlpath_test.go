@@ -45,4 +45,42 @@ func TestUrlPath(t *testing.T) {
 		assert.Nil(t, err)
 		assert.False(t, result)
 	})
+
+	t.Run("should expose the expected path as a literal", func(t *testing.T) {
+		assert.Equal(t, &Literal{Value: "/test/hello"}, URLPath("/test/hello").Literal)
+		assert.Equal(t, &Literal{Value: "/test", Prefix: true}, ToHavePrefix("/test").Literal)
+		assert.Equal(t, &Literal{Value: "GET"}, ToEqualFold("GET").Literal)
+		assert.Equal(t, &Literal{Value: "ok"}, ToEqual("ok").Literal)
+		assert.Nil(t, ToEqual(10).Literal)
+	})
+}
+
+func TestURLPathPrefix(t *testing.T) {
+	u, _ := url.Parse("http://localhost:8080/Assets/js/app.js")
+
+	testCases := []struct {
+		prefix   string
+		value    any
+		expected bool
+	}{
+		{"/assets/", u, true},
+		{"/assets/js/app.js", *u, true},
+		{"/assets", "http://localhost:8080/assets", true},
+		{"/static/", u, false},
+		{"/assets/js/app.js.map", u, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.prefix, func(t *testing.T) {
+			result, err := URLPathPrefix(tc.prefix).Matches(tc.value, Args{})
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+
+	assert.Equal(t, &Literal{Value: "/assets/", Prefix: true}, URLPathPrefix("/assets/").Literal)
+	assert.Panics(t, func() {
+		_, _ = URLPathPrefix("/test").Matches(10, Args{})
+	})
 }
@@ -0,0 +1,74 @@
+package expect
+
+import (
+	"fmt"
+
+	"github.com/vitorsalgado/mocha/v3/internal/xmlx"
+)
+
+// XPath applies the provided matcher to the result of the XPath expression, evaluated on the XML request body.
+// When the expression selects nodes, the matcher receives the text of the first one, and it doesn't match if no node
+// is selected. Other expressions pass their result to the matcher as a string, float64 or bool.
+// Element and attribute names are matched by their local names, so namespace prefixes are optional.
+// Example:
+//
+//	XML: <user id="1"><name>dev</name></user>
+//	XPath("/user/name", ToEqual("dev")) will return true
+//	XPath("/user/@id", ToEqual("1")) will return true
+//	XPath("count(//name)", ToEqual(float64(1))) will return true
+func XPath(expression string, matcher Matcher) Matcher {
+	x, compileErr := xmlx.Compile(expression)
+
+	m := Matcher{}
+	m.Name = "XPath"
+	m.DescribeMismatch = func(p string, v any) string {
+		return fmt.Sprintf("matcher %s applied on xpath %s did not match", matcher.Name, expression)
+	}
+	m.Matches = func(v any, args Args) (bool, error) {
+		if compileErr != nil {
+			return false, compileErr
+		}
+
+		doc, ok := xmlDocument(v)
+		if !ok {
+			return false, nil
+		}
+
+		result := x.Evaluate(doc)
+
+		if nodes, ok := result.([]*xmlx.Node); ok {
+			if len(nodes) == 0 {
+				return false, nil
+			}
+
+			result = nodes[0].Text()
+		}
+
+		return matcher.Matches(result, args)
+	}
+
+	return m
+}
+
+// xmlDocument returns the XML document from a parsed XML body, a string or a []byte.
+func xmlDocument(v any) (*xmlx.Node, bool) {
+	var data []byte
+
+	switch e := v.(type) {
+	case *xmlx.Node:
+		return e, true
+	case string:
+		data = []byte(e)
+	case []byte:
+		data = e
+	default:
+		return nil, false
+	}
+
+	doc, err := xmlx.Parse(data)
+	if err != nil {
+		return nil, false
+	}
+
+	return doc, true
+}
@@ -0,0 +1,57 @@
+package expect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3/internal/xmlx"
+)
+
+func TestXPath(t *testing.T) {
+	t.Parallel()
+
+	body := `<user id="1"><name>dev</name><roles><role>admin</role><role>qa</role></roles></user>`
+	doc, err := xmlx.Parse([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		matcher  Matcher
+		expected bool
+	}{
+		{"element text", XPath("/user/name", ToEqual("dev")), true},
+		{"attribute", XPath("/user/@id", ToEqual("1")), true},
+		{"predicate", XPath("//role[2]", ToEqual("qa")), true},
+		{"count", XPath("count(//role)", ToEqual(float64(2))), true},
+		{"boolean", XPath("//role = 'admin'", ToEqual(true)), true},
+		{"different value", XPath("/user/name", ToEqual("qa")), false},
+		{"no nodes", XPath("/user/email", ToBePresent()), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, v := range []any{doc, body, []byte(body)} {
+				res, err := tc.matcher.Matches(v, emptyArgs())
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expected, res)
+			}
+		})
+	}
+
+	t.Run("should not match values that are not xml", func(t *testing.T) {
+		for _, v := range []any{nil, 10, "not xml", map[string]any{}} {
+			res, err := XPath("/user", ToBePresent()).Matches(v, emptyArgs())
+			assert.Nil(t, err)
+			assert.False(t, res)
+		}
+	})
+
+	t.Run("should return an error for invalid expressions", func(t *testing.T) {
+		res, err := XPath("/user[", ToBePresent()).Matches(doc, emptyArgs())
+		assert.NotNil(t, err)
+		assert.False(t, res)
+	})
+}
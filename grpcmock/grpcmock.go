@@ -0,0 +1,509 @@
+// Package grpcmock implements a gRPC mock server that serves the mocks of a mocha.Mocha instance.
+package grpcmock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/internal/mimetypes"
+)
+
+// Config defines the gRPC mock server configurations.
+type Config struct {
+	// Addr is the TCP address the gRPC mock server listens on. Defaults to a random port on 127.0.0.1.
+	Addr string
+
+	// DescriptorSets are paths to binary FileDescriptorSet files, like the ones generated by
+	// protoc --include_imports --descriptor_set_out.
+	// Methods not found in these files are looked up in the Go types registered by generated code.
+	DescriptorSets []string
+
+	// ServerOptions are extra options for the gRPC server, like grpc.Creds.
+	ServerOptions []grpc.ServerOption
+}
+
+// Server serves gRPC mocks, sharing the mock storage, scenarios, request journal and events with the HTTP mock server.
+// Request messages are decoded to JSON, so they can be matched like HTTP request bodies.
+// Use New to init a new Server.
+type Server struct {
+	m        *mocha.Mocha
+	t        mocha.T
+	config   Config
+	server   *grpc.Server
+	listener net.Listener
+	files    *protoregistry.Files
+	mu       sync.Mutex
+}
+
+var _json = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+// New creates a new gRPC mock Server that serves the mocks added to the given mocha.Mocha instance.
+func New(t mocha.T, m *mocha.Mocha, config ...Config) *Server {
+	s := &Server{m: m, t: t}
+	if len(config) > 0 {
+		s.config = config[0]
+	}
+
+	return s
+}
+
+// Method inits a mock for the gRPC method with the given full name, like "/package.Service/Method".
+// Request messages are decoded to JSON, so they can be matched with Body and JSONPath matchers.
+// Client streaming methods are matched against the list of all messages sent by the client.
+// Bidirectional streaming methods are matched once for each message sent by the client.
+// Request metadata is matched with Header. Use it along with OK or Error replies.
+func Method(fullMethod string) *mocha.MockBuilder {
+	if !strings.HasPrefix(fullMethod, "/") {
+		fullMethod = "/" + fullMethod
+	}
+
+	return mocha.Post(expect.URLPath(fullMethod)).Header(headers.ContentType, expect.ToHavePrefix(mimetypes.GRPC))
+}
+
+// Start starts the gRPC mock server, returning its address.
+// It returns the current address when the server is already running.
+func (s *Server) Start() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		return s.listener.Addr().String()
+	}
+
+	files, err := loadDescriptorSets(s.config.DescriptorSets)
+	if err != nil {
+		s.t.Errorf("failed to start grpc mock server. reason=%v", err)
+		s.t.FailNow()
+		return ""
+	}
+
+	addr := s.config.Addr
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		s.t.Errorf("failed to start grpc mock server. reason=%v", err)
+		s.t.FailNow()
+		return ""
+	}
+
+	opts := make([]grpc.ServerOption, 0, len(s.config.ServerOptions)+1)
+	opts = append(opts, s.config.ServerOptions...)
+	opts = append(opts, grpc.UnknownServiceHandler(s.handle))
+
+	s.files = files
+	s.listener = listener
+	s.server = grpc.NewServer(opts...)
+
+	go s.server.Serve(listener)
+
+	return listener.Addr().String()
+}
+
+// Addr returns the gRPC mock server address.
+// It is empty if the server is not running.
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return ""
+	}
+
+	return s.listener.Addr().String()
+}
+
+// Close stops the gRPC mock server, closing all open connections.
+func (s *Server) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.server == nil {
+		return
+	}
+
+	s.server.Stop()
+	s.server = nil
+	s.listener = nil
+}
+
+// CloseOnCleanup adds the gRPC mock server Close to the Cleanup.
+func (s *Server) CloseOnCleanup(t mocha.Cleanable) *Server {
+	t.Cleanup(s.Close)
+	return s
+}
+
+// loadDescriptorSets reads all given FileDescriptorSet files into a single registry.
+func loadDescriptorSets(paths []string) (*protoregistry.Files, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := make(map[string]struct{})
+
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading descriptor set %s. reason=%v", path, err)
+		}
+
+		fds := &descriptorpb.FileDescriptorSet{}
+		if err = proto.Unmarshal(b, fds); err != nil {
+			return nil, fmt.Errorf("error decoding descriptor set %s. reason=%v", path, err)
+		}
+
+		for _, file := range fds.File {
+			if _, ok := seen[file.GetName()]; ok {
+				continue
+			}
+
+			seen[file.GetName()] = struct{}{}
+			set.File = append(set.File, file)
+		}
+	}
+
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("error loading descriptor sets. reason=%v", err)
+	}
+
+	return files, nil
+}
+
+// findMethod looks up the method descriptor in the configured descriptor sets and then in the global registry.
+// It returns nil when the method is not found.
+func (s *Server) findMethod(fullMethod string) protoreflect.MethodDescriptor {
+	name := strings.TrimPrefix(fullMethod, "/")
+	i := strings.LastIndex(name, "/")
+	if i < 0 {
+		return nil
+	}
+
+	service, method := name[:i], name[i+1:]
+
+	for _, files := range []*protoregistry.Files{s.files, protoregistry.GlobalFiles} {
+		if files == nil {
+			continue
+		}
+
+		d, err := files.FindDescriptorByName(protoreflect.FullName(service))
+		if err != nil {
+			continue
+		}
+
+		sd, ok := d.(protoreflect.ServiceDescriptor)
+		if !ok {
+			continue
+		}
+
+		if md := sd.Methods().ByName(protoreflect.Name(method)); md != nil {
+			return md
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) handle(_ any, stream grpc.ServerStream) error {
+	fullMethod, _ := grpc.MethodFromServerStream(stream)
+	md := s.findMethod(fullMethod)
+	if md == nil {
+		// unknown methods are still served, so they are recorded and reported like other requests.
+		return s.serve(stream, nil, fullMethod, nil, true)
+	}
+
+	if md.IsStreamingClient() && md.IsStreamingServer() {
+		return s.serveBidi(stream, md, fullMethod)
+	}
+
+	body, err := receive(stream, md)
+	if err != nil {
+		return err
+	}
+
+	return s.serve(stream, md, fullMethod, body, true)
+}
+
+// serveBidi serves each message sent by the client as soon as it is received, until the client closes the stream.
+// Only the header metadata of the first reply is sent. The stream ends early when a reply has an error status.
+func (s *Server) serveBidi(stream grpc.ServerStream, md protoreflect.MethodDescriptor, fullMethod string) error {
+	for first := true; ; first = false {
+		msg := dynamicpb.NewMessage(md.Input())
+		err := stream.RecvMsg(msg)
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		body, err := messageToJSON(msg)
+		if err != nil {
+			return err
+		}
+
+		if err = s.serve(stream, md, fullMethod, body, first); err != nil {
+			return err
+		}
+	}
+}
+
+// serve matches the request body with the stored mocks and sends the reply of the matched one.
+// It returns the reply status error, which is nil for codes.OK.
+func (s *Server) serve(
+	stream grpc.ServerStream,
+	md protoreflect.MethodDescriptor,
+	fullMethod string,
+	parsedBody any,
+	sendHeader bool,
+) error {
+	rawBody, _ := json.Marshal(parsedBody)
+	r := newRequest(stream.Context(), fullMethod, rawBody)
+
+	var replyStatus error
+
+	err := s.m.Serve(&mocha.Call{Request: r, Body: rawBody, ParsedBody: parsedBody}, func(mock *mocha.Mock) (int, error) {
+		if md == nil {
+			return int(codes.Unimplemented), errMethodNotFound(fullMethod)
+		}
+
+		rep, ok := mock.Reply.(*Reply)
+		if !ok {
+			return int(codes.Internal), status.Errorf(codes.Internal,
+				"mock %d %s must use a grpc reply. got %T", mock.ID, mock.Name, mock.Reply)
+		}
+
+		res, err := rep.BuildResponse(r, mock, s.m.Parameters())
+		if err != nil {
+			return int(status.Code(err)), err
+		}
+
+		if res.Delay > 0 {
+			select {
+			case <-time.After(res.Delay):
+			case <-stream.Context().Done():
+				return int(codes.Canceled), stream.Context().Err()
+			}
+		}
+
+		if err = send(stream, md, res, sendHeader); err != nil {
+			return int(status.Code(err)), err
+		}
+
+		replyStatus = replyStatusError(res)
+
+		return int(res.Code), nil
+	})
+
+	if err != nil && md == nil {
+		return errMethodNotFound(fullMethod)
+	}
+
+	var notMatched *mocha.NotMatchedError
+	if errors.As(err, &notMatched) {
+		return status.Error(codes.Unimplemented, notMatched.Error())
+	}
+
+	if err != nil {
+		return status.Convert(err).Err()
+	}
+
+	return replyStatus
+}
+
+func errMethodNotFound(fullMethod string) error {
+	return status.Errorf(codes.Unimplemented,
+		"method %s not found in descriptor sets or registered types", fullMethod)
+}
+
+// receive reads the request messages, decoding them to JSON values.
+// Client streaming methods result in a list with all messages sent by the client.
+func receive(stream grpc.ServerStream, md protoreflect.MethodDescriptor) (any, error) {
+	if !md.IsStreamingClient() {
+		msg := dynamicpb.NewMessage(md.Input())
+		if err := stream.RecvMsg(msg); err != nil {
+			return nil, err
+		}
+
+		return messageToJSON(msg)
+	}
+
+	list := make([]any, 0)
+
+	for {
+		msg := dynamicpb.NewMessage(md.Input())
+		err := stream.RecvMsg(msg)
+		if errors.Is(err, io.EOF) {
+			return list, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		v, err := messageToJSON(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, v)
+	}
+}
+
+// send sends the response metadata and messages.
+func send(stream grpc.ServerStream, md protoreflect.MethodDescriptor, res *Response, sendHeader bool) error {
+	if sendHeader && len(res.Header) > 0 {
+		if err := stream.SetHeader(res.Header); err != nil {
+			return err
+		}
+	}
+
+	stream.SetTrailer(res.Trailer)
+
+	// unary methods reply either a message or an error status.
+	if md.IsStreamingServer() || res.Code == codes.OK {
+		if !md.IsStreamingServer() && len(res.Messages) != 1 {
+			return status.Errorf(codes.Internal,
+				"unary method %s must reply exactly one message. got %d", md.FullName(), len(res.Messages))
+		}
+
+		for i, v := range res.Messages {
+			msg, err := toMessage(md.Output(), v)
+			if err != nil {
+				return status.Errorf(codes.Internal, "error encoding response message %d. reason=%v", i, err)
+			}
+
+			if err = stream.SendMsg(msg); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// replyStatusError builds the response status error, including its details. It returns nil for codes.OK.
+func replyStatusError(res *Response) error {
+	if res.Code == codes.OK {
+		return nil
+	}
+
+	st := &spb.Status{Code: int32(res.Code), Message: res.Message}
+
+	for _, detail := range res.Details {
+		a, err := anypb.New(detail)
+		if err != nil {
+			return status.Errorf(codes.Internal, "error encoding status details. reason=%v", err)
+		}
+
+		st.Details = append(st.Details, a)
+	}
+
+	return status.ErrorProto(st)
+}
+
+// messageToJSON converts a protobuf message to a JSON value, keeping the field names used in the proto file.
+func messageToJSON(msg proto.Message) (any, error) {
+	b, err := _json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var v any
+	if err = json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// toMessage converts a response message value to a protobuf message of the given type.
+func toMessage(desc protoreflect.MessageDescriptor, v any) (proto.Message, error) {
+	var b []byte
+
+	switch e := v.(type) {
+	case proto.Message:
+		return e, nil
+	case string:
+		b = []byte(e)
+	case []byte:
+		b = e
+	default:
+		var err error
+		b, err = json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	msg := dynamicpb.NewMessage(desc)
+	if err := protojson.Unmarshal(b, msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// newRequest creates an HTTP request representing a gRPC call, so it can be matched like HTTP ones.
+// The method is POST, the path is the full method name and the headers come from the request metadata.
+func newRequest(ctx context.Context, fullMethod string, body []byte) *http.Request {
+	r := (&http.Request{
+		Method:     http.MethodPost,
+		URL:        &url.URL{Path: fullMethod},
+		RequestURI: fullMethod,
+		Proto:      "HTTP/2.0",
+		ProtoMajor: 2,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}).WithContext(ctx)
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for k, values := range md {
+		if k == ":authority" {
+			r.Host = strings.Join(values, "")
+			continue
+		}
+
+		if strings.HasPrefix(k, ":") {
+			continue
+		}
+
+		for _, v := range values {
+			r.Header.Add(k, v)
+		}
+	}
+
+	if r.Header.Get(headers.ContentType) == "" {
+		r.Header.Set(headers.ContentType, mimetypes.GRPC)
+	}
+
+	return r
+}
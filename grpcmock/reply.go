@@ -0,0 +1,119 @@
+package grpcmock
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/vitorsalgado/mocha/v3/params"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+type (
+	// Reply represents a gRPC response stub.
+	// Use OK or Error to init a new Reply.
+	Reply struct {
+		response *Response
+	}
+
+	// Response defines the gRPC response that will be served once a gRPC mock is matched.
+	Response struct {
+		// Header is the metadata sent before the response messages.
+		Header metadata.MD
+
+		// Trailer is the metadata sent after the response messages.
+		Trailer metadata.MD
+
+		// Messages are the response messages. Unary methods must reply exactly one message when Code is codes.OK.
+		// Values can be a proto.Message, a JSON string or []byte, or any value that encodes to a JSON
+		// compatible with the method output type.
+		Messages []any
+
+		// Code is the gRPC status code.
+		Code codes.Code
+
+		// Message is the gRPC status message.
+		Message string
+
+		// Details are the gRPC status details.
+		Details []proto.Message
+
+		// Delay is the time to wait before sending the response.
+		Delay time.Duration
+	}
+)
+
+var _ reply.Reply = (*Reply)(nil)
+
+// OK inits a new Reply with the status code codes.OK.
+func OK() *Reply {
+	return &Reply{response: &Response{
+		Header:   metadata.MD{},
+		Trailer:  metadata.MD{},
+		Messages: make([]any, 0),
+		Code:     codes.OK,
+		Details:  make([]proto.Message, 0),
+	}}
+}
+
+// Error inits a new Reply with the given status code and message.
+func Error(code codes.Code, message string) *Reply {
+	return OK().Status(code, message)
+}
+
+// Message adds a response message. Server streaming methods send all messages in order.
+// The value can be a proto.Message, a JSON string or []byte, or any value that encodes to a JSON
+// compatible with the method output type.
+func (r *Reply) Message(v any) *Reply {
+	r.response.Messages = append(r.response.Messages, v)
+	return r
+}
+
+// Status sets the gRPC status code and message.
+func (r *Reply) Status(code codes.Code, message string) *Reply {
+	r.response.Code = code
+	r.response.Message = message
+	return r
+}
+
+// Details adds details to the gRPC status, like the ones from the package errdetails.
+func (r *Reply) Details(details ...proto.Message) *Reply {
+	r.response.Details = append(r.response.Details, details...)
+	return r
+}
+
+// Header adds a header metadata.
+func (r *Reply) Header(key, value string) *Reply {
+	r.response.Header.Append(key, value)
+	return r
+}
+
+// Trailer adds a trailer metadata.
+func (r *Reply) Trailer(key, value string) *Reply {
+	r.response.Trailer.Append(key, value)
+	return r
+}
+
+// Delay sets a delay before sending the response.
+func (r *Reply) Delay(duration time.Duration) *Reply {
+	r.response.Delay = duration
+	return r
+}
+
+// Build always fails, as gRPC replies are only served by the gRPC mock server.
+func (r *Reply) Build(_ *http.Request, _ reply.M, _ params.P) (*reply.Response, error) {
+	return nil, errors.New("grpc replies can only be served by the grpc mock server")
+}
+
+// BuildResponse builds a Response based on previously provided values.
+func (r *Reply) BuildResponse(_ *http.Request, _ reply.M, _ params.P) (*Response, error) {
+	res := *r.response
+	res.Header = r.response.Header.Copy()
+	res.Trailer = r.response.Trailer.Copy()
+
+	return &res, nil
+}
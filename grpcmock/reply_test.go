@@ -0,0 +1,43 @@
+package grpcmock
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestReply(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "/grpc.testing.TestService/UnaryCall", nil)
+	rep := OK().
+		Message(map[string]any{"username": "dev"}).
+		Header("x-header", "hello").
+		Trailer("x-trailer", "bye").
+		Delay(10 * time.Millisecond)
+
+	res, err := rep.BuildResponse(req, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, codes.OK, res.Code)
+	assert.Len(t, res.Messages, 1)
+	assert.Equal(t, []string{"hello"}, res.Header.Get("x-header"))
+	assert.Equal(t, []string{"bye"}, res.Trailer.Get("x-trailer"))
+	assert.Equal(t, 10*time.Millisecond, res.Delay)
+
+	res.Header.Set("x-header", "changed")
+	again, _ := rep.BuildResponse(req, nil, nil)
+	assert.Equal(t, []string{"hello"}, again.Header.Get("x-header"))
+
+	_, err = rep.Build(req, nil, nil)
+	assert.NotNil(t, err)
+}
+
+func TestError(t *testing.T) {
+	res, err := Error(codes.NotFound, "not found").BuildResponse(nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, codes.NotFound, res.Code)
+	assert.Equal(t, "not found", res.Message)
+}
@@ -2,7 +2,11 @@ package mocha
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -16,23 +20,67 @@ import (
 )
 
 type mockHandler struct {
+	ctx         context.Context
 	mocks       storage
 	scenarios   scenarioStore
 	bodyParsers []RequestBodyParser
 	params      params.P
+	journal     *requestJournal
+	strict      *strictLog
+	recorder    *recorder
+	notMatched  *fallback
+	onError     *fallback
 	evt         *hooks.Emitter
 	t           T
 }
 
+// fallback holds a Reply served when no mock could serve a request.
+// Its Mock is used only to keep track of the hits.
+type fallback struct {
+	reply reply.Reply
+	mock  *Mock
+}
+
+func newFallback(name string, rep reply.Reply) *fallback {
+	if rep == nil {
+		return nil
+	}
+
+	mock := newMock()
+	mock.Name = name
+	mock.Reply = rep
+
+	return &fallback{reply: rep, mock: mock}
+}
+
 func newHandler(
+	ctx context.Context,
 	storage storage,
 	scenarios scenarioStore,
 	bodyParsers []RequestBodyParser,
 	params params.P,
+	journal *requestJournal,
+	strict *strictLog,
+	recorder *recorder,
+	notMatchedReply reply.Reply,
+	errorReply reply.Reply,
 	evt *hooks.Emitter,
 	t T,
 ) *mockHandler {
-	return &mockHandler{mocks: storage, scenarios: scenarios, bodyParsers: bodyParsers, params: params, evt: evt, t: t}
+	return &mockHandler{
+		ctx:         ctx,
+		mocks:       storage,
+		scenarios:   scenarios,
+		bodyParsers: bodyParsers,
+		params:      params,
+		journal:     journal,
+		strict:      strict,
+		recorder:    recorder,
+		notMatched:  newFallback("not matched fallback", notMatchedReply),
+		onError:     newFallback("error fallback", errorReply),
+		evt:         evt,
+		t:           t,
+	}
 }
 
 func (h *mockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -41,71 +89,152 @@ func (h *mockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	h.evt.Emit(hooks.OnRequest{Request: er, StartedAt: start})
 
-	parsedBody, err := parseRequestBody(r, h.bodyParsers)
+	parsedBody, rawBody, decoded, err := parseRequestBody(r, h.bodyParsers)
+
+	// record the request before any reply runs, as replies are allowed to modify it.
+	entry := &RecordedRequest{
+		Request:    r.Clone(context.Background()),
+		Body:       rawBody,
+		ParsedBody: parsedBody,
+		StartedAt:  start}
+	entry.Request.Body = http.NoBody
+
+	defer h.recordEntry(entry)
+
+	// replyReq is the request passed to replies.
+	replyReq := r
+
+	fail := func(err error) {
+		entry.Err = err
+		h.evt.Emit(hooks.OnError{Request: hooks.FromRequest(r), Err: err})
+
+		if res := h.buildFallback(replyReq, h.onError); res != nil {
+			defer closeBody(res)
+
+			entry.Status = res.Status
+			h.writeResponse(w, res)
+			return
+		}
+
+		entry.Status = http.StatusTeapot
+		respondError(w, err)
+	}
+
 	if err != nil {
-		respondError(w, r, h.evt, err)
+		fail(err)
 		return
 	}
 
+	// values computed for the request are available to replies through the request context.
+	values := &reply.RequestValues{ParsedBody: parsedBody, RawBody: rawBody}
+	r = r.WithContext(reply.ContextWithRequestValues(r.Context(), values))
+	replyReq = r
+
+	// decoded bodies are passed to replies without the Content-Encoding header, as they are no longer encoded.
+	if decoded {
+		replyReq = withoutContentCoding(r, rawBody)
+	}
+
 	// match current request with all eligible stored matchers in order to find one mock.
 	args := expect.Args{
 		RequestInfo: &expect.RequestInfo{Request: r, ParsedBody: parsedBody},
 		Params:      h.params}
 	result, err := findMockForRequest(h.mocks, args)
 	if err != nil {
-		respondError(w, r, h.evt, err)
+		fail(err)
 		return
 	}
 
 	if !result.Matches {
-		respondNonMatched(w, r, result, h.evt)
+		if h.recorder != nil && h.recorder.forward != nil {
+			entry.Mismatch = emitNonMatched(r, result, h.evt)
+
+			res, err := h.recorder.forward.Build(replyReq, nil, h.params)
+			if err != nil {
+				fail(err)
+				return
+			}
+
+			defer closeBody(res)
+
+			entry.Forwarded = true
+			entry.Status = res.Status
+			h.record(entry, res)
+			h.writeResponse(w, res)
+
+			return
+		}
+
+		if h.notMatched != nil {
+			entry.Mismatch = emitNonMatched(r, result, h.evt)
+
+			if res := h.buildFallback(replyReq, h.notMatched); res != nil {
+				defer closeBody(res)
+
+				entry.Status = res.Status
+
+				if _, ok := h.notMatched.reply.(*reply.ProxyReply); ok {
+					h.record(entry, res)
+				}
+
+				h.writeResponse(w, res)
+
+				return
+			}
+
+			entry.Status = http.StatusTeapot
+			writeNonMatched(w, result)
+			return
+		}
+
+		entry.Status = http.StatusTeapot
+		entry.Mismatch = respondNonMatched(w, r, result, h.evt)
 		return
 	}
 
 	mock := result.Matched
 
-	if mock.Repeat > 0 && mock.Hits()+1 > mock.Repeat {
-		respondError(w, r, h.evt,
-			fmt.Errorf("mock is set to respond only %d times. current hits is %d", mock.Repeat, mock.Hits()))
+	if err = h.checkMock(mock); err != nil {
+		fail(err)
 		return
 	}
 
 	if mock.ScenarioName != "" {
-		scn, ok := h.scenarios.FetchByName(mock.ScenarioName)
+		values.ScenarioName = mock.ScenarioName
 
-		if !ok && mock.ScenarioRequiredState == _scenarioStateStarted {
-			scn = h.scenarios.CreateNewIfNeeded(mock.ScenarioName)
-			ok = true
+		if scn, ok := h.scenarios.FetchByName(mock.ScenarioName); ok {
+			values.ScenarioState = scn.State
 		}
+	}
 
-		if ok {
-			if scn.State == mock.ScenarioRequiredState {
-				if mock.ScenarioNewState != "" {
-					scn.State = mock.ScenarioNewState
-					h.scenarios.Save(scn)
-				}
-			} else {
-				respondError(w, r, h.evt,
-					fmt.Errorf("expected mock id=%d scenario=%s to be %s. got %s",
-						mock.ID, mock.ScenarioName, mock.ScenarioRequiredState, scn.State))
-				return
-			}
-		}
+	if upgrader, ok := mock.Reply.(reply.Upgrader); ok {
+		h.upgrade(r, w, mock, upgrader, entry)
+
+		h.evt.Emit(hooks.OnRequestMatch{
+			Request:            er,
+			ResponseDefinition: hooks.Response{Status: entry.Status, Header: make(http.Header)},
+			Mock:               hooks.Mock{ID: mock.ID, Name: mock.Name},
+			Elapsed:            time.Since(start)})
+
+		return
 	}
 
 	// get the reply for the mock, after running all possible matchers.
-	res, err := result.Matched.Reply.Build(r, mock, h.params)
+	res, err := result.Matched.Reply.Build(replyReq, mock, h.params)
 	if err != nil {
 		h.t.Logf(err.Error())
-		respondError(w, r, h.evt, err)
+		fail(err)
 		return
 	}
 
+	// bodies, like files and streams, are closed even when the response is not written.
+	defer closeBody(res)
+
 	// map the response using mock mappers.
-	mapperArgs := reply.ResponseMapperArgs{Request: r, Parameters: h.params}
+	mapperArgs := reply.ResponseMapperArgs{Request: replyReq, Parameters: h.params}
 	for _, mapper := range res.Mappers {
 		if err = mapper(res, mapperArgs); err != nil {
-			respondError(w, r, h.evt, err)
+			fail(err)
 			return
 		}
 	}
@@ -113,27 +242,15 @@ func (h *mockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// success
 	mock.Hit()
 
-	// if a delay is set, it will wait before continuing serving the mocked response.
-	if res.Delay > 0 {
-		<-time.After(res.Delay)
-	}
+	entry.Matched = true
+	entry.Mock = mock
+	entry.Status = res.Status
 
-	for k := range res.Header {
-		w.Header().Add(k, res.Header.Get(k))
+	if _, ok := mock.Reply.(*reply.ProxyReply); ok {
+		h.record(entry, res)
 	}
 
-	w.WriteHeader(res.Status)
-
-	if res.Body != nil {
-		scanner := bufio.NewScanner(res.Body)
-		for scanner.Scan() {
-			w.Write(scanner.Bytes())
-		}
-
-		if scanner.Err() != nil {
-			h.t.Logf("error writing response body: error=%v", scanner.Err())
-		}
-	}
+	h.writeResponse(w, res)
 
 	// run post actions.
 	paArgs := PostActionArgs{Request: r, Response: res, Mock: mock, Params: h.params}
@@ -151,21 +268,222 @@ func (h *mockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Elapsed:            time.Since(start)})
 }
 
-func respondNonMatched(w http.ResponseWriter, r *http.Request, result *findResult, evt *hooks.Emitter) {
-	e := hooks.OnRequestNotMatched{Request: hooks.FromRequest(r), Result: hooks.Result{Details: make([]hooks.ResultDetail, 0)}}
+// recordEntry adds the entry to the request journal and, in strict mode, to the strict log.
+func (h *mockHandler) recordEntry(entry *RecordedRequest) {
+	entry.Elapsed = time.Since(entry.StartedAt)
+	h.journal.Record(entry)
 
-	if result.ClosestMatch != nil {
-		e.Result.HasClosestMatch = true
-		e.Result.ClosestMatch = hooks.Mock{ID: result.ClosestMatch.ID, Name: result.ClosestMatch.Name}
+	if h.strict != nil {
+		h.strict.Record(entry)
 	}
+}
 
-	for _, detail := range result.MismatchDetails {
-		e.Result.Details = append(e.Result.Details,
-			hooks.ResultDetail{Name: detail.Name, Description: detail.Description, Target: detail.Target})
+// checkMock checks if the matched mock can serve the request, considering its repeat limit and scenario state.
+// The scenario state is moved forward when the mock defines a new state.
+func (h *mockHandler) checkMock(mock *Mock) error {
+	if mock.Repeat > 0 && mock.Hits()+1 > mock.Repeat {
+		return fmt.Errorf("mock is set to respond only %d times. current hits is %d", mock.Repeat, mock.Hits())
 	}
 
-	evt.Emit(e)
+	if mock.ScenarioName == "" {
+		return nil
+	}
+
+	scn, ok := h.scenarios.FetchByName(mock.ScenarioName)
+
+	if !ok && mock.ScenarioRequiredState == _scenarioStateStarted {
+		scn = h.scenarios.CreateNewIfNeeded(mock.ScenarioName)
+		ok = true
+	}
+
+	if !ok {
+		return nil
+	}
+
+	if scn.State != mock.ScenarioRequiredState {
+		return fmt.Errorf("expected mock id=%d scenario=%s to be %s. got %s",
+			mock.ID, mock.ScenarioName, mock.ScenarioRequiredState, scn.State)
+	}
+
+	if mock.ScenarioNewState != "" {
+		scn.State = mock.ScenarioNewState
+		h.scenarios.Save(scn)
+	}
+
+	return nil
+}
 
+// upgrade hands the connection over to the reply, like WebSocket ones.
+// The mock hit is only counted once the reply takes over the connection, so failed handshakes are not counted.
+// The connection is closed when the mock server closes. Post actions don't run for upgraded connections.
+func (h *mockHandler) upgrade(r *http.Request, w http.ResponseWriter, mock *Mock, upgrader reply.Upgrader, entry *RecordedRequest) {
+	entry.Matched = true
+	entry.Mock = mock
+
+	w = &upgradeWriter{ResponseWriter: w, entry: entry, onHijack: func() {
+		mock.Hit()
+		entry.Status = http.StatusSwitchingProtocols
+	}}
+
+	// hijacked connections are not tracked by the server, so they are closed using the handler context.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-h.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := upgrader.Upgrade(w, r.WithContext(ctx), mock, h.params); err != nil {
+		entry.Err = err
+		h.t.Logf("\nerror serving upgraded connection for mock %d %s. error=%v", mock.ID, mock.Name, err)
+	}
+}
+
+// upgradeWriter is the http.ResponseWriter passed to upgrade replies.
+// It records the status of failed handshakes and calls onHijack once the connection is taken over.
+type upgradeWriter struct {
+	http.ResponseWriter
+	entry    *RecordedRequest
+	onHijack func()
+}
+
+func (w *upgradeWriter) WriteHeader(status int) {
+	w.entry.Status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *upgradeWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking connections")
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w.onHijack()
+
+	return conn, rw, nil
+}
+
+// writeResponse writes the mocked Response, waiting for its delay first.
+// The body is copied as is. Streaming responses, with Response.Flush set, have each chunk flushed to the client
+// when the http.ResponseWriter supports it.
+func (h *mockHandler) writeResponse(w http.ResponseWriter, res *reply.Response) {
+	// if a delay is set, it will wait before continuing serving the mocked response.
+	if res.Delay > 0 {
+		<-time.After(res.Delay)
+	}
+
+	// every header value is written, so multi-value headers are served as defined.
+	for k, values := range res.Header {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+
+	for _, cookie := range res.Cookies {
+		http.SetCookie(w, cookie)
+	}
+
+	// trailers must be declared before writing the header.
+	for k := range res.Trailer {
+		w.Header().Add(headers.Trailer, k)
+	}
+
+	w.WriteHeader(res.Status)
+
+	if res.Body != nil {
+		var dst io.Writer = w
+		if f, ok := w.(http.Flusher); ok && res.Flush {
+			dst = &flushWriter{w: w, f: f}
+		}
+
+		if _, err := io.Copy(dst, res.Body); err != nil {
+			h.t.Logf("\nerror writing response body: error=%v", err)
+		}
+	}
+
+	for k, values := range res.Trailer {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+}
+
+// closeBody closes response bodies that implement io.Closer, like files and streaming pipes.
+func closeBody(res *reply.Response) {
+	if c, ok := res.Body.(io.Closer); ok {
+		c.Close()
+	}
+}
+
+// flushWriter flushes every write to the client.
+type flushWriter struct {
+	w io.Writer
+	f http.Flusher
+}
+
+func (fw *flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	fw.f.Flush()
+
+	return n, err
+}
+
+// buildFallback builds the Response for the given fallback, running its mappers.
+// It returns nil when there is no fallback or when it fails, so the default response should be served.
+func (h *mockHandler) buildFallback(r *http.Request, fb *fallback) *reply.Response {
+	if fb == nil {
+		return nil
+	}
+
+	res, err := fb.reply.Build(r, fb.mock, h.params)
+	if err != nil {
+		h.t.Logf("\nerror building %s reply. error=%v", fb.mock.Name, err)
+		return nil
+	}
+
+	mapperArgs := reply.ResponseMapperArgs{Request: r, Parameters: h.params}
+	for _, mapper := range res.Mappers {
+		if err = mapper(res, mapperArgs); err != nil {
+			h.t.Logf("\nerror mapping %s reply. error=%v", fb.mock.Name, err)
+			closeBody(res)
+			return nil
+		}
+	}
+
+	fb.mock.Hit()
+
+	return res
+}
+
+// record persists the request and response pair when recording is enabled.
+func (h *mockHandler) record(entry *RecordedRequest, res *reply.Response) {
+	if h.recorder == nil {
+		return
+	}
+
+	if err := h.recorder.Record(entry, res); err != nil {
+		h.t.Logf("\nerror recording request %s %s. error=%v", entry.Request.Method, entry.Request.URL, err)
+	}
+}
+
+func respondNonMatched(w http.ResponseWriter, r *http.Request, result *findResult, evt *hooks.Emitter) *hooks.Result {
+	res := emitNonMatched(r, result, evt)
+	writeNonMatched(w, result)
+
+	return res
+}
+
+// writeNonMatched writes the default response for requests that didn't match any mock.
+func writeNonMatched(w http.ResponseWriter, result *findResult) {
 	builder := strings.Builder{}
 	builder.WriteString("REQUEST DID NOT MATCH.\n")
 
@@ -186,9 +504,27 @@ func respondNonMatched(w http.ResponseWriter, r *http.Request, result *findResul
 	w.Write([]byte(builder.String()))
 }
 
-func respondError(w http.ResponseWriter, r *http.Request, evt *hooks.Emitter, err error) {
-	evt.Emit(hooks.OnError{Request: hooks.FromRequest(r), Err: err})
+// emitNonMatched emits the OnRequestNotMatched event, returning its result.
+func emitNonMatched(r *http.Request, result *findResult, evt *hooks.Emitter) *hooks.Result {
+	e := hooks.OnRequestNotMatched{Request: hooks.FromRequest(r), Result: hooks.Result{Details: make([]hooks.ResultDetail, 0)}}
+
+	if result.ClosestMatch != nil {
+		e.Result.HasClosestMatch = true
+		e.Result.ClosestMatch = hooks.Mock{ID: result.ClosestMatch.ID, Name: result.ClosestMatch.Name}
+	}
+
+	for _, detail := range result.MismatchDetails {
+		e.Result.Details = append(e.Result.Details,
+			hooks.ResultDetail{Name: detail.Name, Description: detail.Description, Target: detail.Target})
+	}
+
+	evt.Emit(e)
+
+	return &e.Result
+}
 
+// respondError writes the default response for errors that occurred while serving a request.
+func respondError(w http.ResponseWriter, err error) {
 	w.Header().Add(headers.ContentType, mimetypes.TextPlain)
 	w.WriteHeader(http.StatusTeapot)
 
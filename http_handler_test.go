@@ -0,0 +1,207 @@
+package mocha
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/testutil"
+	"github.com/vitorsalgado/mocha/v3/params"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func TestResponseBody(t *testing.T) {
+	m := New(t)
+	m.Start()
+	defer m.Close()
+
+	get := func(path string) (*http.Response, []byte) {
+		res, err := testutil.Get(m.URL() + path).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer res.Body.Close()
+
+		b, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return res, b
+	}
+
+	t.Run("should keep new lines", func(t *testing.T) {
+		body := "{\n  \"name\": \"dev\"\n}\n\n"
+		m.AddMocks(Get(expect.URLPath("/pretty")).Reply(reply.OK().BodyString(body)))
+
+		_, b := get("/pretty")
+
+		assert.Equal(t, body, string(b))
+	})
+
+	t.Run("should write binary bodies", func(t *testing.T) {
+		body := make([]byte, 256*1024)
+		rand.New(rand.NewSource(1)).Read(body)
+
+		m.AddMocks(Get(expect.URLPath("/binary")).Reply(reply.OK().Body(body)))
+
+		_, b := get("/binary")
+
+		assert.Equal(t, body, b)
+	})
+
+	t.Run("should write lines longer than 64KB", func(t *testing.T) {
+		body := strings.Repeat("a", 128*1024)
+		m.AddMocks(Get(expect.URLPath("/long")).Reply(reply.OK().BodyString(body)))
+
+		_, b := get("/long")
+
+		assert.Equal(t, body, string(b))
+	})
+
+	t.Run("should set the content length of plain bodies", func(t *testing.T) {
+		m.AddMocks(Get(expect.URLPath("/plain")).Reply(reply.OK().BodyString("hello")))
+
+		res, b := get("/plain")
+
+		assert.Equal(t, "hello", string(b))
+		assert.Equal(t, int64(5), res.ContentLength)
+		assert.Empty(t, res.TransferEncoding)
+	})
+
+	t.Run("should write all header values", func(t *testing.T) {
+		m.AddMocks(Get(expect.URLPath("/headers")).
+			Reply(reply.OK().Header("x-tags", "a").Header("x-tags", "b").Header("x-single", "c")))
+
+		res, _ := get("/headers")
+
+		assert.Equal(t, []string{"a", "b"}, res.Header.Values("x-tags"))
+		assert.Equal(t, []string{"c"}, res.Header.Values("x-single"))
+	})
+
+	t.Run("should write cookies", func(t *testing.T) {
+		m.AddMocks(Get(expect.URLPath("/cookies")).
+			Reply(reply.OK().
+				Cookie(http.Cookie{Name: "session", Value: "abc"}).
+				ExpireCookie(http.Cookie{Name: "old"})))
+
+		res, _ := get("/cookies")
+		cookies := res.Cookies()
+
+		assert.Len(t, cookies, 2)
+		assert.Equal(t, "session", cookies[0].Name)
+		assert.Equal(t, "abc", cookies[0].Value)
+		assert.Equal(t, "old", cookies[1].Name)
+		assert.Equal(t, -1, cookies[1].MaxAge)
+	})
+}
+
+func TestResponseBody_Flush(t *testing.T) {
+	m := New(t)
+	m.Start()
+	defer m.Close()
+
+	received := make(chan struct{})
+
+	m.AddMocks(Get(expect.URLPath("/stream")).
+		ReplyFunction(func(r *http.Request, _ reply.M, _ params.P) (*reply.Response, error) {
+			pr, pw := io.Pipe()
+
+			go func() {
+				pw.Write([]byte("first\n"))
+
+				select {
+				case <-received:
+				case <-time.After(5 * time.Second):
+				}
+
+				pw.Write([]byte("second\n"))
+				pw.Close()
+			}()
+
+			return &reply.Response{Status: http.StatusOK, Header: make(http.Header), Body: pr, Flush: true}, nil
+		}))
+
+	var reader *bufio.Reader
+	first := make(chan string)
+
+	go func() {
+		res, err := testutil.Get(m.URL() + "/stream").Do()
+		if err != nil {
+			first <- err.Error()
+			return
+		}
+
+		t.Cleanup(func() { res.Body.Close() })
+
+		reader = bufio.NewReader(res.Body)
+		line, _ := reader.ReadString('\n')
+		first <- line
+	}()
+
+	select {
+	case line := <-first:
+		assert.Equal(t, "first\n", line)
+	case <-time.After(2 * time.Second):
+		t.Fatal("first chunk was not flushed")
+	}
+
+	close(received)
+
+	rest, err := io.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.True(t, bytes.Equal([]byte("second\n"), rest))
+}
+
+type closeTracker struct {
+	io.Reader
+	closed chan struct{}
+}
+
+func (c *closeTracker) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func TestResponseBody_CloseOnMapperError(t *testing.T) {
+	m := New(t)
+	m.Start()
+	defer m.Close()
+
+	body := &closeTracker{Reader: strings.NewReader("hello"), closed: make(chan struct{})}
+
+	m.AddMocks(Get(expect.URLPath("/test")).
+		ReplyFunction(func(r *http.Request, _ reply.M, _ params.P) (*reply.Response, error) {
+			return &reply.Response{
+				Status:  http.StatusOK,
+				Header:  make(http.Header),
+				Body:    body,
+				Mappers: []reply.ResponseMapper{func(*reply.Response, reply.ResponseMapperArgs) error { return errors.New("fail") }},
+			}, nil
+		}))
+
+	res, err := testutil.Get(m.URL() + "/test").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusTeapot, res.StatusCode)
+
+	select {
+	case <-body.closed:
+	case <-time.After(time.Second):
+		t.Fatal("response body was not closed")
+	}
+}
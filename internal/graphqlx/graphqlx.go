@@ -0,0 +1,541 @@
+// Package graphqlx implements a lightweight GraphQL query parser, used to match requests by their query shape.
+// It only keeps operations, fields and fragments. Arguments, variable definitions and directives are skipped.
+package graphqlx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Operation types.
+const (
+	Query        = "query"
+	Mutation     = "mutation"
+	Subscription = "subscription"
+)
+
+// _maxFragmentDepth limits fragment spread resolution, protecting against cyclic fragments.
+const _maxFragmentDepth = 32
+
+var (
+	// ErrOperationNotFound is returned when the requested operation is not present in the document.
+	ErrOperationNotFound = errors.New("graphql operation not found")
+)
+
+type (
+	// Document is a parsed GraphQL query document.
+	Document struct {
+		Operations []*Operation
+	}
+
+	// Operation is a GraphQL operation definition.
+	Operation struct {
+		// Type is the operation type: query, mutation or subscription.
+		Type string
+
+		// Name is the operation name. It is empty for anonymous operations.
+		Name string
+
+		// Fields are the top level fields selected by the operation, with fragments already resolved.
+		Fields []*Field
+	}
+
+	// Field is a selected GraphQL field.
+	Field struct {
+		// Name is the field name.
+		Name string
+
+		// Alias is the field alias, if any.
+		Alias string
+
+		// Fields are the sub-selected fields, with fragments already resolved.
+		Fields []*Field
+	}
+
+	// selection holds fields and fragment spreads before fragments are resolved.
+	selection struct {
+		field   *Field
+		spread  string
+		inline  []*selection
+		subsels []*selection
+	}
+
+	parser struct {
+		tokens []token
+		pos    int
+	}
+
+	token struct {
+		kind  tokenKind
+		value string
+	}
+
+	tokenKind int
+)
+
+const (
+	tokenName tokenKind = iota
+	tokenPunct
+	tokenString
+	tokenNumber
+	tokenEOF
+)
+
+// Parse parses the given GraphQL query document.
+func Parse(query string) (*Document, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	ops := make([]*Operation, 0)
+	opSelections := make([][]*selection, 0)
+	fragments := make(map[string][]*selection)
+
+	for p.peek().kind != tokenEOF {
+		tok := p.peek()
+
+		switch {
+		case tok.kind == tokenPunct && tok.value == "{":
+			sels, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+
+			ops = append(ops, &Operation{Type: Query})
+			opSelections = append(opSelections, sels)
+
+		case tok.kind == tokenName && (tok.value == Query || tok.value == Mutation || tok.value == Subscription):
+			p.next()
+			op := &Operation{Type: tok.value}
+
+			if p.peek().kind == tokenName {
+				op.Name = p.next().value
+			}
+
+			if err = p.skipVariableDefinitions(); err != nil {
+				return nil, err
+			}
+
+			if err = p.skipDirectives(); err != nil {
+				return nil, err
+			}
+
+			sels, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+
+			ops = append(ops, op)
+			opSelections = append(opSelections, sels)
+
+		case tok.kind == tokenName && tok.value == "fragment":
+			p.next()
+
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+
+			if on, err := p.expectName(); err != nil || on != "on" {
+				return nil, fmt.Errorf("graphql: expected \"on\" after fragment %s", name)
+			}
+
+			if _, err = p.expectName(); err != nil {
+				return nil, err
+			}
+
+			if err = p.skipDirectives(); err != nil {
+				return nil, err
+			}
+
+			sels, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+
+			fragments[name] = sels
+
+		default:
+			return nil, fmt.Errorf("graphql: unexpected token %q", tok.value)
+		}
+	}
+
+	if len(ops) == 0 {
+		return nil, errors.New("graphql: document has no operations")
+	}
+
+	for i, op := range ops {
+		fields, err := resolve(opSelections[i], fragments, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		op.Fields = fields
+	}
+
+	return &Document{Operations: ops}, nil
+}
+
+// Operation returns the operation with the given name.
+// If the name is empty, the document must contain a single operation, which is returned.
+func (d *Document) Operation(name string) (*Operation, error) {
+	if name == "" {
+		if len(d.Operations) == 1 {
+			return d.Operations[0], nil
+		}
+
+		return nil, fmt.Errorf("%w: operation name is required for documents with multiple operations", ErrOperationNotFound)
+	}
+
+	for _, op := range d.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrOperationNotFound, name)
+}
+
+// HasField checks if the operation selects the field in the given dot separated path, like "user.address.city".
+// Each path segment matches either a field name or a field alias.
+func (o *Operation) HasField(path string) bool {
+	fields := o.Fields
+
+	for _, segment := range strings.Split(path, ".") {
+		var found *Field
+
+		for _, f := range fields {
+			if f.Name == segment || f.Alias == segment {
+				found = f
+				break
+			}
+		}
+
+		if found == nil {
+			return false
+		}
+
+		fields = found.Fields
+	}
+
+	return true
+}
+
+// resolve converts selections to fields, expanding fragment spreads and inline fragments.
+func resolve(sels []*selection, fragments map[string][]*selection, depth int) ([]*Field, error) {
+	if depth > _maxFragmentDepth {
+		return nil, errors.New("graphql: maximum fragment depth exceeded")
+	}
+
+	fields := make([]*Field, 0, len(sels))
+
+	for _, sel := range sels {
+		switch {
+		case sel.field != nil:
+			sub, err := resolve(sel.subsels, fragments, depth)
+			if err != nil {
+				return nil, err
+			}
+
+			sel.field.Fields = sub
+			fields = append(fields, sel.field)
+
+		case sel.spread != "":
+			fragment, ok := fragments[sel.spread]
+			if !ok {
+				return nil, fmt.Errorf("graphql: unknown fragment %s", sel.spread)
+			}
+
+			sub, err := resolve(fragment, fragments, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, sub...)
+
+		default:
+			sub, err := resolve(sel.inline, fragments, depth)
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, sub...)
+		}
+	}
+
+	return fields, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) isPunct(value string) bool {
+	tok := p.peek()
+	return tok.kind == tokenPunct && tok.value == value
+}
+
+func (p *parser) expectPunct(value string) error {
+	if !p.isPunct(value) {
+		return fmt.Errorf("graphql: expected %q. got %q", value, p.peek().value)
+	}
+
+	p.next()
+
+	return nil
+}
+
+func (p *parser) expectName() (string, error) {
+	tok := p.next()
+	if tok.kind != tokenName {
+		return "", fmt.Errorf("graphql: expected a name. got %q", tok.value)
+	}
+
+	return tok.value, nil
+}
+
+func (p *parser) selectionSet() ([]*selection, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+
+	sels := make([]*selection, 0)
+
+	for !p.isPunct("}") {
+		if p.peek().kind == tokenEOF {
+			return nil, errors.New("graphql: unterminated selection set")
+		}
+
+		sel, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+
+		sels = append(sels, sel)
+	}
+
+	p.next()
+
+	return sels, nil
+}
+
+func (p *parser) selection() (*selection, error) {
+	if p.isPunct("...") {
+		p.next()
+
+		tok := p.peek()
+		if tok.kind == tokenName && tok.value != "on" {
+			p.next()
+			return &selection{spread: tok.value}, p.skipDirectives()
+		}
+
+		if tok.kind == tokenName && tok.value == "on" {
+			p.next()
+
+			if _, err := p.expectName(); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := p.skipDirectives(); err != nil {
+			return nil, err
+		}
+
+		sels, err := p.selectionSet()
+		if err != nil {
+			return nil, err
+		}
+
+		return &selection{inline: sels}, nil
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	field := &Field{Name: name}
+
+	if p.isPunct(":") {
+		p.next()
+
+		field.Alias = name
+		if field.Name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = p.skipParens(); err != nil {
+		return nil, err
+	}
+
+	if err = p.skipDirectives(); err != nil {
+		return nil, err
+	}
+
+	sel := &selection{field: field}
+
+	if p.isPunct("{") {
+		if sel.subsels, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+
+	return sel, nil
+}
+
+func (p *parser) skipVariableDefinitions() error {
+	return p.skipParens()
+}
+
+func (p *parser) skipDirectives() error {
+	for p.isPunct("@") {
+		p.next()
+
+		if _, err := p.expectName(); err != nil {
+			return err
+		}
+
+		if err := p.skipParens(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// skipParens skips a balanced parenthesis group, like arguments and variable definitions, if present.
+func (p *parser) skipParens() error {
+	if !p.isPunct("(") {
+		return nil
+	}
+
+	depth := 0
+
+	for {
+		tok := p.next()
+
+		switch {
+		case tok.kind == tokenEOF:
+			return errors.New("graphql: unterminated parenthesis")
+		case tok.kind == tokenPunct && tok.value == "(":
+			depth++
+		case tok.kind == tokenPunct && tok.value == ")":
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+func tokenize(src string) ([]token, error) {
+	tokens := make([]token, 0)
+	src = strings.TrimPrefix(src, "\ufeff")
+	i := 0
+
+	for i < len(src) {
+		c := src[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+
+		case c == '#':
+			for i < len(src) && src[i] != '\n' && src[i] != '\r' {
+				i++
+			}
+
+		case strings.HasPrefix(src[i:], "..."):
+			tokens = append(tokens, token{kind: tokenPunct, value: "..."})
+			i += 3
+
+		case strings.ContainsRune("!$&():=@[]{}|", rune(c)):
+			tokens = append(tokens, token{kind: tokenPunct, value: string(c)})
+			i++
+
+		case isNameStart(c):
+			start := i
+			for i < len(src) && (isNameStart(src[i]) || isDigit(src[i])) {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenName, value: src[start:i]})
+
+		case isDigit(c) || c == '-':
+			start := i
+			i++
+			for i < len(src) && (isDigit(src[i]) || strings.ContainsRune(".eE+-", rune(src[i]))) {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, value: src[start:i]})
+
+		case strings.HasPrefix(src[i:], `"""`):
+			end := strings.Index(src[i+3:], `"""`)
+			for end >= 0 && src[i+3+end-1] == '\\' {
+				next := strings.Index(src[i+3+end+3:], `"""`)
+				if next < 0 {
+					end = -1
+					break
+				}
+
+				end += 3 + next
+			}
+
+			if end < 0 {
+				return nil, errors.New("graphql: unterminated block string")
+			}
+
+			tokens = append(tokens, token{kind: tokenString, value: src[i+3 : i+3+end]})
+			i += 3 + end + 3
+
+		case c == '"':
+			start := i
+			i++
+			for i < len(src) && src[i] != '"' {
+				if src[i] == '\n' {
+					return nil, errors.New("graphql: unterminated string")
+				}
+
+				if src[i] == '\\' {
+					i++
+				}
+
+				i++
+			}
+
+			if i >= len(src) {
+				return nil, errors.New("graphql: unterminated string")
+			}
+
+			i++
+			tokens = append(tokens, token{kind: tokenString, value: src[start:i]})
+
+		default:
+			return nil, fmt.Errorf("graphql: unexpected character %q", c)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,85 @@
+package graphqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(`
+		# fetch a user
+		query GetUser($id: ID!, $withPosts: Boolean = false) @cached(ttl: 10) {
+			user(id: $id, filter: { status: "open", tags: ["a", "b"] }) {
+				id
+				fullName: name
+				...UserAddress
+				... on Admin { permissions }
+				posts(first: 10) @include(if: $withPosts) { title }
+			}
+		}
+
+		mutation UpdateUser { updateUser(input: { name: """block "string" """ }) { id } }
+
+		fragment UserAddress on User { address { city } }`)
+
+	assert.Nil(t, err)
+	assert.Len(t, doc.Operations, 2)
+
+	op, err := doc.Operation("GetUser")
+	assert.Nil(t, err)
+	assert.Equal(t, Query, op.Type)
+	assert.True(t, op.HasField("user"))
+	assert.True(t, op.HasField("user.id"))
+	assert.True(t, op.HasField("user.name"))
+	assert.True(t, op.HasField("user.fullName"))
+	assert.True(t, op.HasField("user.address.city"))
+	assert.True(t, op.HasField("user.permissions"))
+	assert.True(t, op.HasField("user.posts.title"))
+	assert.False(t, op.HasField("user.email"))
+	assert.False(t, op.HasField("updateUser"))
+
+	op, err = doc.Operation("UpdateUser")
+	assert.Nil(t, err)
+	assert.Equal(t, Mutation, op.Type)
+	assert.True(t, op.HasField("updateUser.id"))
+
+	_, err = doc.Operation("")
+	assert.ErrorIs(t, err, ErrOperationNotFound)
+
+	_, err = doc.Operation("Other")
+	assert.ErrorIs(t, err, ErrOperationNotFound)
+}
+
+func TestParse_Anonymous(t *testing.T) {
+	doc, err := Parse(`{ viewer { login } }`)
+	assert.Nil(t, err)
+
+	op, err := doc.Operation("")
+	assert.Nil(t, err)
+	assert.Equal(t, Query, op.Type)
+	assert.Equal(t, "", op.Name)
+	assert.True(t, op.HasField("viewer.login"))
+}
+
+func TestParse_Invalid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{"empty", ""},
+		{"unterminated selection", "query { user { id }"},
+		{"unterminated string", `query { user(id: "1) { id } }`},
+		{"unknown fragment", "query { ...Missing }"},
+		{"cyclic fragment", "query { ...A } fragment A on T { ...B } fragment B on T { ...A }"},
+		{"unexpected token", "user { id }"},
+		{"unexpected character", "query { user ; }"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.query)
+			assert.NotNil(t, err)
+		})
+	}
+}
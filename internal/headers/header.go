@@ -3,11 +3,22 @@ package headers
 
 // Common HTTP headers.
 const (
-	Accept        = "Accept"
-	ContentType   = "Content-Type"
-	Vary          = "Vary"
-	Origin        = "Origin"
-	ContentLength = "Content-Length"
+	Accept          = "Accept"
+	AcceptEncoding  = "Accept-Encoding"
+	ContentType     = "Content-Type"
+	ContentEncoding = "Content-Encoding"
+	Vary            = "Vary"
+	Origin          = "Origin"
+	ContentLength   = "Content-Length"
+	CacheControl    = "Cache-Control"
+	Trailer         = "Trailer"
+	SOAPAction      = "SOAPAction"
+	ETag            = "ETag"
+	IfNoneMatch     = "If-None-Match"
+	IfModifiedSince = "If-Modified-Since"
+	LastModified    = "Last-Modified"
+	Range           = "Range"
+	ContentRange    = "Content-Range"
 
 	AccessControlAllowOrigin      = "Access-Control-Allow-Origin"
 	AccessControlAllowMethods     = "Access-Control-Allow-Methods"
@@ -0,0 +1,448 @@
+// Package jsonschema implements a JSON Schema draft 2020-12 validator, used to match JSON request bodies.
+// References are resolved within the schema document, using JSON pointers, $id and $anchor. $dynamicRef is resolved
+// like $ref. The format keyword is treated as an annotation, so it is not validated.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	root *schema
+}
+
+// schema is a compiled schema object or boolean schema.
+type schema struct {
+	location string
+	always   *bool
+
+	ref       string
+	refTarget *schema
+
+	types    []string
+	enum     []any
+	constant any
+	hasConst bool
+
+	multipleOf       *float64
+	maximum          *float64
+	exclusiveMaximum *float64
+	minimum          *float64
+	exclusiveMinimum *float64
+
+	maxLength *int
+	minLength *int
+	pattern   *regexp.Regexp
+
+	maxItems    *int
+	minItems    *int
+	uniqueItems bool
+	maxContains *int
+	minContains *int
+
+	maxProperties     *int
+	minProperties     *int
+	required          []string
+	dependentRequired map[string][]string
+
+	allOf []*schema
+	anyOf []*schema
+	oneOf []*schema
+	not   *schema
+	when  *schema
+	then  *schema
+	other *schema
+
+	properties            map[string]*schema
+	propertyOrder         []string
+	patternProperties     []*patternSchema
+	additionalProperties  *schema
+	propertyNames         *schema
+	dependentSchemas      map[string]*schema
+	unevaluatedProperties *schema
+
+	prefixItems      []*schema
+	items            *schema
+	contains         *schema
+	unevaluatedItems *schema
+}
+
+type patternSchema struct {
+	pattern *regexp.Regexp
+	schema  *schema
+}
+
+// compiler holds the state of a schema compilation.
+type compiler struct {
+	locations map[string]*schema
+	anchors   map[string]*schema
+	refs      []*schema
+}
+
+// Compile parses and compiles the given JSON Schema document.
+func Compile(data []byte) (*Schema, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid json schema: %w", err)
+	}
+
+	c := &compiler{locations: make(map[string]*schema), anchors: make(map[string]*schema)}
+
+	root, err := c.compile(doc, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range c.refs {
+		target, err := c.resolve(s.ref)
+		if err != nil {
+			return nil, err
+		}
+
+		s.refTarget = target
+	}
+
+	return &Schema{root: root}, nil
+}
+
+// compile compiles the raw schema located at the given base URI and JSON pointer.
+func (c *compiler) compile(raw any, base, pointer string) (*schema, error) {
+	s := &schema{location: base + "#" + pointer}
+
+	if b, ok := raw.(bool); ok {
+		s.always = &b
+		c.locations[s.location] = s
+
+		return s, nil
+	}
+
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("schema at %s must be an object or a boolean", s.location)
+	}
+
+	c.locations[s.location] = s
+
+	if id, ok := obj["$id"].(string); ok {
+		resolved, err := resolveURI(base, id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid $id %s at %s: %w", id, s.location, err)
+		}
+
+		base, _, _ = strings.Cut(resolved, "#")
+		pointer = ""
+		c.locations[base+"#"] = s
+	}
+
+	for _, keyword := range []string{"$anchor", "$dynamicAnchor"} {
+		if anchor, ok := obj[keyword].(string); ok {
+			c.anchors[base+"#"+anchor] = s
+		}
+	}
+
+	for _, keyword := range []string{"$ref", "$dynamicRef"} {
+		if ref, ok := obj[keyword].(string); ok {
+			resolved, err := resolveURI(base, ref)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %s at %s: %w", keyword, ref, s.location, err)
+			}
+
+			s.ref = resolved
+			c.refs = append(c.refs, s)
+
+			break
+		}
+	}
+
+	var err error
+
+	sub := func(keyword string) (*schema, error) {
+		v, ok := obj[keyword]
+		if !ok {
+			return nil, nil
+		}
+
+		return c.compile(v, base, pointer+"/"+escape(keyword))
+	}
+
+	list := func(keyword string) ([]*schema, error) {
+		v, ok := obj[keyword]
+		if !ok {
+			return nil, nil
+		}
+
+		arr, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s at %s must be an array", keyword, s.location)
+		}
+
+		schemas := make([]*schema, len(arr))
+		for i, item := range arr {
+			schemas[i], err = c.compile(item, base, fmt.Sprintf("%s/%s/%d", pointer, keyword, i))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return schemas, nil
+	}
+
+	dict := func(keyword string) (map[string]*schema, []string, error) {
+		v, ok := obj[keyword]
+		if !ok {
+			return nil, nil, nil
+		}
+
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, nil, fmt.Errorf("%s at %s must be an object", keyword, s.location)
+		}
+
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		schemas := make(map[string]*schema, len(m))
+		for _, k := range keys {
+			schemas[k], err = c.compile(m[k], base, pointer+"/"+escape(keyword)+"/"+escape(k))
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		return schemas, keys, nil
+	}
+
+	// definitions are compiled so references can point to them.
+	for _, keyword := range []string{"$defs", "definitions"} {
+		if _, _, err = dict(keyword); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.allOf, err = list("allOf"); err != nil {
+		return nil, err
+	}
+	if s.anyOf, err = list("anyOf"); err != nil {
+		return nil, err
+	}
+	if s.oneOf, err = list("oneOf"); err != nil {
+		return nil, err
+	}
+	if s.prefixItems, err = list("prefixItems"); err != nil {
+		return nil, err
+	}
+
+	for keyword, dst := range map[string]**schema{
+		"not":                   &s.not,
+		"if":                    &s.when,
+		"then":                  &s.then,
+		"else":                  &s.other,
+		"additionalProperties":  &s.additionalProperties,
+		"propertyNames":         &s.propertyNames,
+		"unevaluatedProperties": &s.unevaluatedProperties,
+		"items":                 &s.items,
+		"contains":              &s.contains,
+		"unevaluatedItems":      &s.unevaluatedItems,
+	} {
+		if *dst, err = sub(keyword); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.properties, s.propertyOrder, err = dict("properties"); err != nil {
+		return nil, err
+	}
+	if s.dependentSchemas, _, err = dict("dependentSchemas"); err != nil {
+		return nil, err
+	}
+
+	patterns, keys, err := dict("patternProperties")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range keys {
+		re, err := regexp.Compile(k)
+		if err != nil {
+			return nil, fmt.Errorf("invalid patternProperties %s at %s: %w", k, s.location, err)
+		}
+
+		s.patternProperties = append(s.patternProperties, &patternSchema{pattern: re, schema: patterns[k]})
+	}
+
+	if err = c.assertions(s, obj); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// assertions compiles the keywords that validate the instance directly.
+func (c *compiler) assertions(s *schema, obj map[string]any) error {
+	switch t := obj["type"].(type) {
+	case nil:
+	case string:
+		s.types = []string{t}
+	case []any:
+		for _, v := range t {
+			name, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("type at %s must be a string or an array of strings", s.location)
+			}
+
+			s.types = append(s.types, name)
+		}
+	default:
+		return fmt.Errorf("type at %s must be a string or an array of strings", s.location)
+	}
+
+	if v, ok := obj["enum"]; ok {
+		enum, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("enum at %s must be an array", s.location)
+		}
+
+		s.enum = enum
+	}
+
+	s.constant, s.hasConst = obj["const"]
+
+	for keyword, dst := range map[string]**float64{
+		"multipleOf":       &s.multipleOf,
+		"maximum":          &s.maximum,
+		"exclusiveMaximum": &s.exclusiveMaximum,
+		"minimum":          &s.minimum,
+		"exclusiveMinimum": &s.exclusiveMinimum,
+	} {
+		if v, ok := obj[keyword]; ok {
+			n, ok := v.(float64)
+			if !ok {
+				return fmt.Errorf("%s at %s must be a number", keyword, s.location)
+			}
+
+			*dst = &n
+		}
+	}
+
+	for keyword, dst := range map[string]**int{
+		"maxLength":     &s.maxLength,
+		"minLength":     &s.minLength,
+		"maxItems":      &s.maxItems,
+		"minItems":      &s.minItems,
+		"maxContains":   &s.maxContains,
+		"minContains":   &s.minContains,
+		"maxProperties": &s.maxProperties,
+		"minProperties": &s.minProperties,
+	} {
+		if v, ok := obj[keyword]; ok {
+			n, ok := v.(float64)
+			if !ok || n < 0 || n != float64(int(n)) {
+				return fmt.Errorf("%s at %s must be a non-negative integer", keyword, s.location)
+			}
+
+			i := int(n)
+			*dst = &i
+		}
+	}
+
+	if v, ok := obj["pattern"].(string); ok {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %s at %s: %w", v, s.location, err)
+		}
+
+		s.pattern = re
+	}
+
+	s.uniqueItems, _ = obj["uniqueItems"].(bool)
+
+	var err error
+
+	if s.required, err = stringList(obj["required"]); err != nil {
+		return fmt.Errorf("required at %s %w", s.location, err)
+	}
+
+	if v, ok := obj["dependentRequired"].(map[string]any); ok {
+		s.dependentRequired = make(map[string][]string, len(v))
+
+		for k, names := range v {
+			if s.dependentRequired[k], err = stringList(names); err != nil {
+				return fmt.Errorf("dependentRequired at %s %w", s.location, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolve returns the schema referenced by the given absolute URI.
+func (c *compiler) resolve(ref string) (*schema, error) {
+	uri, fragment, _ := strings.Cut(ref, "#")
+
+	if fragment == "" || strings.HasPrefix(fragment, "/") {
+		pointer, err := url.PathUnescape(fragment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference %s: %w", ref, err)
+		}
+
+		if s, ok := c.locations[uri+"#"+pointer]; ok {
+			return s, nil
+		}
+	} else if s, ok := c.anchors[uri+"#"+fragment]; ok {
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("unresolved reference %s. only references within the schema document are supported", ref)
+}
+
+func resolveURI(base, ref string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+
+	return b.ResolveReference(r).String(), nil
+}
+
+func stringList(v any) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("must be an array of strings")
+	}
+
+	list := make([]string, len(arr))
+	for i, item := range arr {
+		str, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be an array of strings")
+		}
+
+		list[i] = str
+	}
+
+	return list, nil
+}
+
+// escape escapes a JSON pointer reference token.
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,159 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validate(t *testing.T, schema, instance string) []Violation {
+	s, err := Compile([]byte(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v any
+	if err = json.Unmarshal([]byte(instance), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	return s.Validate(v)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		schema   string
+		instance string
+		valid    bool
+	}{
+		{"true schema", `true`, `{"a": 1}`, true},
+		{"false schema", `false`, `1`, false},
+		{"type", `{"type": "string"}`, `"a"`, true},
+		{"type mismatch", `{"type": "string"}`, `1`, false},
+		{"integer", `{"type": "integer"}`, `1.0`, true},
+		{"integer mismatch", `{"type": "integer"}`, `1.5`, false},
+		{"number accepts integers", `{"type": "number"}`, `1`, true},
+		{"type list", `{"type": ["string", "null"]}`, `null`, true},
+		{"enum", `{"enum": ["a", 1, {"b": [1]}]}`, `{"b": [1]}`, true},
+		{"enum mismatch", `{"enum": ["a", 1]}`, `"b"`, false},
+		{"const", `{"const": {"a": 1}}`, `{"a": 1}`, true},
+		{"const mismatch", `{"const": {"a": 1}}`, `{"a": 2}`, false},
+		{"multipleOf", `{"multipleOf": 0.1}`, `0.3`, true},
+		{"multipleOf mismatch", `{"multipleOf": 2}`, `3`, false},
+		{"range", `{"minimum": 1, "maximum": 3}`, `3`, true},
+		{"maximum", `{"maximum": 3}`, `4`, false},
+		{"exclusive maximum", `{"exclusiveMaximum": 3}`, `3`, false},
+		{"exclusive minimum", `{"exclusiveMinimum": 1}`, `1`, false},
+		{"length counts code points", `{"maxLength": 2}`, `"ãé"`, true},
+		{"min length", `{"minLength": 2}`, `"a"`, false},
+		{"pattern", `{"pattern": "^[a-z]+$"}`, `"abc"`, true},
+		{"pattern mismatch", `{"pattern": "^[a-z]+$"}`, `"ab1"`, false},
+		{"ignores other types", `{"minLength": 10, "maximum": 1, "required": ["a"]}`, `[]`, true},
+		{"items", `{"items": {"type": "integer"}}`, `[1, 2]`, true},
+		{"items mismatch", `{"items": {"type": "integer"}}`, `[1, "2"]`, false},
+		{"prefix items", `{"prefixItems": [{"type": "string"}], "items": {"type": "integer"}}`, `["a", 1]`, true},
+		{"prefix items and items false", `{"prefixItems": [{"type": "string"}], "items": false}`, `["a", 1]`, false},
+		{"array size", `{"minItems": 1, "maxItems": 2}`, `[1, 2, 3]`, false},
+		{"unique items", `{"uniqueItems": true}`, `[1, {"a": 1}, {"a": 2}]`, true},
+		{"unique items mismatch", `{"uniqueItems": true}`, `[{"a": 1}, {"a": 1}]`, false},
+		{"contains", `{"contains": {"const": 2}}`, `[1, 2]`, true},
+		{"contains mismatch", `{"contains": {"const": 2}}`, `[1, 3]`, false},
+		{"min contains", `{"contains": {"type": "integer"}, "minContains": 2}`, `[1, "a"]`, false},
+		{"max contains", `{"contains": {"type": "integer"}, "maxContains": 1}`, `[1, 2]`, false},
+		{"required", `{"required": ["a", "b"]}`, `{"a": 1, "b": null}`, true},
+		{"required mismatch", `{"required": ["a", "b"]}`, `{"a": 1}`, false},
+		{"properties", `{"properties": {"a": {"type": "string"}}}`, `{"a": "x", "b": 1}`, true},
+		{"properties mismatch", `{"properties": {"a": {"type": "string"}}}`, `{"a": 1}`, false},
+		{"additional properties", `{"properties": {"a": {}}, "patternProperties": {"^x-": {}}, "additionalProperties": false}`, `{"a": 1, "x-b": 2}`, true},
+		{"additional properties mismatch", `{"properties": {"a": {}}, "additionalProperties": false}`, `{"a": 1, "b": 2}`, false},
+		{"additional properties schema", `{"additionalProperties": {"type": "integer"}}`, `{"a": "1"}`, false},
+		{"pattern properties", `{"patternProperties": {"^n_": {"type": "number"}}}`, `{"n_a": "x"}`, false},
+		{"property names", `{"propertyNames": {"maxLength": 2}}`, `{"abc": 1}`, false},
+		{"object size", `{"minProperties": 1, "maxProperties": 1}`, `{}`, false},
+		{"dependent required", `{"dependentRequired": {"a": ["b"]}}`, `{"a": 1}`, false},
+		{"dependent schemas", `{"dependentSchemas": {"a": {"required": ["b"]}}}`, `{"c": 1}`, true},
+		{"dependent schemas mismatch", `{"dependentSchemas": {"a": {"required": ["b"]}}}`, `{"a": 1}`, false},
+		{"all of", `{"allOf": [{"type": "integer"}, {"minimum": 2}]}`, `1`, false},
+		{"any of", `{"anyOf": [{"type": "integer"}, {"type": "string"}]}`, `"a"`, true},
+		{"any of mismatch", `{"anyOf": [{"type": "integer"}, {"type": "string"}]}`, `true`, false},
+		{"one of", `{"oneOf": [{"type": "integer"}, {"minimum": 2}]}`, `1`, true},
+		{"one of matching both", `{"oneOf": [{"type": "integer"}, {"minimum": 2}]}`, `3`, false},
+		{"not", `{"not": {"type": "string"}}`, `"a"`, false},
+		{"if then", `{"if": {"properties": {"a": {"const": 1}}}, "then": {"required": ["b"]}, "else": {"required": ["c"]}}`, `{"a": 1, "b": 1}`, true},
+		{"if then mismatch", `{"if": {"properties": {"a": {"const": 1}}}, "then": {"required": ["b"]}}`, `{"a": 1}`, false},
+		{"if else mismatch", `{"if": {"properties": {"a": {"const": 1}}}, "else": {"required": ["c"]}}`, `{"a": 2}`, false},
+		{"unevaluated properties", `{"allOf": [{"properties": {"a": {}}}], "properties": {"b": {}}, "unevaluatedProperties": false}`, `{"a": 1, "b": 1}`, true},
+		{"unevaluated properties mismatch", `{"allOf": [{"properties": {"a": {}}}], "unevaluatedProperties": false}`, `{"a": 1, "b": 1}`, false},
+		{"unevaluated properties from failed branches", `{"anyOf": [{"properties": {"a": {"type": "string"}}}, {"properties": {"b": {}}}], "unevaluatedProperties": false}`, `{"a": 1, "b": 1}`, false},
+		{"unevaluated items", `{"prefixItems": [{}], "contains": {"const": 2}, "unevaluatedItems": false}`, `[1, 2]`, true},
+		{"unevaluated items mismatch", `{"prefixItems": [{}], "unevaluatedItems": false}`, `[1, 2]`, false},
+		{"ref", `{"$defs": {"id": {"type": "integer"}}, "properties": {"id": {"$ref": "#/$defs/id"}}}`, `{"id": "1"}`, false},
+		{"ref to escaped pointer", `{"properties": {"a/b": {"type": "integer"}, "c": {"$ref": "#/properties/a~1b"}}}`, `{"c": 1}`, true},
+		{"recursive ref", `{"type": "object", "properties": {"child": {"$ref": "#"}}, "additionalProperties": false}`, `{"child": {"child": {"x": 1}}}`, false},
+		{"anchor", `{"$defs": {"n": {"$anchor": "name", "type": "string"}}, "items": {"$ref": "#name"}}`, `["a", "b"]`, true},
+		{"id", `{"$id": "https://example.com/root", "items": {"$ref": "item"}, "$defs": {"item": {"$id": "item", "type": "integer"}}}`, `[1, "a"]`, false},
+		{"definitions", `{"definitions": {"a": {"type": "boolean"}}, "$ref": "#/definitions/a"}`, `true`, true},
+		{"format is an annotation", `{"format": "email"}`, `"not an email"`, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			violations := validate(t, tc.schema, tc.instance)
+			assert.Equal(t, tc.valid, len(violations) == 0, "violations: %v", violations)
+		})
+	}
+}
+
+func TestValidate_Violations(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"required": ["id", "name"],
+		"properties": {
+			"id": {"type": "integer"},
+			"tags": {"type": "array", "items": {"type": "string", "minLength": 2}},
+			"address": {
+				"type": "object",
+				"properties": {"city": {"type": "string"}},
+				"additionalProperties": false
+			}
+		}
+	}`
+
+	violations := validate(t, schema, `{"id": "1", "tags": ["ok", "a"], "address": {"city": 10, "zip": "1"}}`)
+
+	assert.Equal(t, []Violation{
+		{"#", "required", `missing required property "name"`},
+		{"#/address/city", "type", "expected type string, got integer"},
+		{"#/address/zip", "additionalProperties", `property "zip" is not allowed`},
+		{"#/id", "type", "expected type integer, got string"},
+		{"#/tags/1", "minLength", "length 1 is less than the minimum length 2"},
+	}, violations)
+	assert.Equal(t, "#/id: expected type integer, got string", violations[3].String())
+}
+
+func TestCompile_Errors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		schema string
+	}{
+		{"invalid json", `{`},
+		{"invalid schema", `1`},
+		{"invalid subschema", `{"properties": {"a": "string"}}`},
+		{"invalid type", `{"type": 1}`},
+		{"invalid pattern", `{"pattern": "("}`},
+		{"invalid pattern properties", `{"patternProperties": {"(": {}}}`},
+		{"invalid min length", `{"minLength": -1}`},
+		{"invalid required", `{"required": [1]}`},
+		{"unresolved reference", `{"$ref": "#/$defs/missing"}`},
+		{"remote reference", `{"$ref": "https://example.com/schema"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Compile([]byte(tc.schema))
+			assert.NotNil(t, err)
+		})
+	}
+}
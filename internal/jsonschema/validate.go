@@ -0,0 +1,509 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxDepth limits the nesting of schema evaluations, protecting against references that never consume the instance.
+const maxDepth = 512
+
+// Violation describes an instance location that doesn't satisfy the schema.
+type Violation struct {
+	// Path is the JSON pointer of the invalid value, prefixed with "#". The root value path is "#".
+	Path string
+
+	// Keyword is the schema keyword that failed.
+	Keyword string
+
+	// Message describes the violation.
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// evaluated holds the object properties and array items evaluated by a successful schema,
+// used by the unevaluatedProperties and unevaluatedItems keywords.
+type evaluated struct {
+	props    map[string]bool
+	items    map[int]bool
+	allItems bool
+}
+
+func (e *evaluated) merge(other *evaluated) {
+	for k := range other.props {
+		e.props[k] = true
+	}
+
+	for i := range other.items {
+		e.items[i] = true
+	}
+
+	e.allItems = e.allItems || other.allItems
+}
+
+// Validate validates the given value against the schema, returning all violations found.
+// The value must be composed by the types produced by json.Unmarshal into an interface value.
+func (s *Schema) Validate(value any) []Violation {
+	violations, _ := s.root.validate(value, "#", 0)
+	return violations
+}
+
+func (s *schema) validate(v any, path string, depth int) ([]Violation, *evaluated) {
+	ev := &evaluated{props: make(map[string]bool), items: make(map[int]bool)}
+
+	if depth > maxDepth {
+		return []Violation{{path, "$ref", "schema evaluation is too deep"}}, ev
+	}
+
+	if s.always != nil {
+		if *s.always {
+			return nil, ev
+		}
+
+		return []Violation{{path, "false", "value is not allowed"}}, ev
+	}
+
+	var violations []Violation
+
+	fail := func(keyword, format string, args ...any) {
+		violations = append(violations, Violation{path, keyword, fmt.Sprintf(format, args...)})
+	}
+
+	// in-place applicators share the evaluated properties and items with this schema, when they succeed.
+	apply := func(sub *schema) bool {
+		vs, subEv := sub.validate(v, path, depth+1)
+		if len(vs) > 0 {
+			return false
+		}
+
+		ev.merge(subEv)
+
+		return true
+	}
+
+	if s.refTarget != nil {
+		vs, subEv := s.refTarget.validate(v, path, depth+1)
+		violations = append(violations, vs...)
+
+		if len(vs) == 0 {
+			ev.merge(subEv)
+		}
+	}
+
+	if len(s.types) > 0 && !hasType(v, s.types) {
+		fail("type", "expected type %s, got %s", strings.Join(s.types, " or "), typeOf(v))
+	}
+
+	if s.enum != nil {
+		found := false
+		for _, e := range s.enum {
+			if equal(v, e) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			fail("enum", "value %s is not one of %s", format(v), format(s.enum))
+		}
+	}
+
+	if s.hasConst && !equal(v, s.constant) {
+		fail("const", "value %s is not equal to %s", format(v), format(s.constant))
+	}
+
+	for _, sub := range s.allOf {
+		vs, subEv := sub.validate(v, path, depth+1)
+		violations = append(violations, vs...)
+
+		if len(vs) == 0 {
+			ev.merge(subEv)
+		}
+	}
+
+	if len(s.anyOf) > 0 {
+		matched := false
+		for _, sub := range s.anyOf {
+			if apply(sub) {
+				matched = true
+			}
+		}
+
+		if !matched {
+			fail("anyOf", "value does not match any of the anyOf schemas")
+		}
+	}
+
+	if len(s.oneOf) > 0 {
+		matched := 0
+		for _, sub := range s.oneOf {
+			if apply(sub) {
+				matched++
+			}
+		}
+
+		if matched != 1 {
+			fail("oneOf", "value matches %d of the oneOf schemas, expected exactly 1", matched)
+		}
+	}
+
+	if s.not != nil {
+		if vs, _ := s.not.validate(v, path, depth+1); len(vs) == 0 {
+			fail("not", "value must not match the not schema")
+		}
+	}
+
+	if s.when != nil {
+		branch := s.other
+		if apply(s.when) {
+			branch = s.then
+		}
+
+		if branch != nil {
+			vs, subEv := branch.validate(v, path, depth+1)
+			violations = append(violations, vs...)
+
+			if len(vs) == 0 {
+				ev.merge(subEv)
+			}
+		}
+	}
+
+	switch e := v.(type) {
+	case float64:
+		violations = append(violations, s.validateNumber(e, path)...)
+	case string:
+		violations = append(violations, s.validateString(e, path)...)
+	case []any:
+		violations = append(violations, s.validateArray(e, path, depth, ev)...)
+	case map[string]any:
+		violations = append(violations, s.validateObject(e, path, depth, ev)...)
+	}
+
+	return violations, ev
+}
+
+func (s *schema) validateNumber(n float64, path string) []Violation {
+	var violations []Violation
+
+	fail := func(keyword, format string, args ...any) {
+		violations = append(violations, Violation{path, keyword, fmt.Sprintf(format, args...)})
+	}
+
+	if s.multipleOf != nil && *s.multipleOf != 0 {
+		q := n / *s.multipleOf
+		if math.IsInf(q, 0) || math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("multipleOf", "value %s is not a multiple of %s", formatNumber(n), formatNumber(*s.multipleOf))
+		}
+	}
+
+	if s.maximum != nil && n > *s.maximum {
+		fail("maximum", "value %s is greater than the maximum %s", formatNumber(n), formatNumber(*s.maximum))
+	}
+
+	if s.exclusiveMaximum != nil && n >= *s.exclusiveMaximum {
+		fail("exclusiveMaximum", "value %s is not less than %s", formatNumber(n), formatNumber(*s.exclusiveMaximum))
+	}
+
+	if s.minimum != nil && n < *s.minimum {
+		fail("minimum", "value %s is less than the minimum %s", formatNumber(n), formatNumber(*s.minimum))
+	}
+
+	if s.exclusiveMinimum != nil && n <= *s.exclusiveMinimum {
+		fail("exclusiveMinimum", "value %s is not greater than %s", formatNumber(n), formatNumber(*s.exclusiveMinimum))
+	}
+
+	return violations
+}
+
+func (s *schema) validateString(str string, path string) []Violation {
+	var violations []Violation
+
+	fail := func(keyword, format string, args ...any) {
+		violations = append(violations, Violation{path, keyword, fmt.Sprintf(format, args...)})
+	}
+
+	length := utf8.RuneCountInString(str)
+
+	if s.maxLength != nil && length > *s.maxLength {
+		fail("maxLength", "length %d is greater than the maximum length %d", length, *s.maxLength)
+	}
+
+	if s.minLength != nil && length < *s.minLength {
+		fail("minLength", "length %d is less than the minimum length %d", length, *s.minLength)
+	}
+
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		fail("pattern", "value %q does not match the pattern %s", str, s.pattern.String())
+	}
+
+	return violations
+}
+
+func (s *schema) validateArray(arr []any, path string, depth int, ev *evaluated) []Violation {
+	var violations []Violation
+
+	fail := func(keyword, format string, args ...any) {
+		violations = append(violations, Violation{path, keyword, fmt.Sprintf(format, args...)})
+	}
+
+	if s.maxItems != nil && len(arr) > *s.maxItems {
+		fail("maxItems", "array has %d items, more than the maximum %d", len(arr), *s.maxItems)
+	}
+
+	if s.minItems != nil && len(arr) < *s.minItems {
+		fail("minItems", "array has %d items, less than the minimum %d", len(arr), *s.minItems)
+	}
+
+	if s.uniqueItems {
+	unique:
+		for i := 0; i < len(arr); i++ {
+			for j := i + 1; j < len(arr); j++ {
+				if equal(arr[i], arr[j]) {
+					fail("uniqueItems", "items %d and %d are equal", i, j)
+					break unique
+				}
+			}
+		}
+	}
+
+	for i, sub := range s.prefixItems {
+		if i >= len(arr) {
+			break
+		}
+
+		vs, _ := sub.validate(arr[i], path+"/"+strconv.Itoa(i), depth+1)
+		violations = append(violations, vs...)
+		ev.items[i] = true
+	}
+
+	if s.items != nil {
+		for i := len(s.prefixItems); i < len(arr); i++ {
+			vs, _ := s.items.validate(arr[i], path+"/"+strconv.Itoa(i), depth+1)
+			violations = append(violations, vs...)
+		}
+
+		ev.allItems = true
+	}
+
+	if s.contains != nil {
+		matches := 0
+		for i, item := range arr {
+			if vs, _ := s.contains.validate(item, path+"/"+strconv.Itoa(i), depth+1); len(vs) == 0 {
+				matches++
+				ev.items[i] = true
+			}
+		}
+
+		minimum := 1
+		if s.minContains != nil {
+			minimum = *s.minContains
+		}
+
+		if matches < minimum {
+			fail("contains", "array contains %d matching items, less than the minimum %d", matches, minimum)
+		}
+
+		if s.maxContains != nil && matches > *s.maxContains {
+			fail("maxContains", "array contains %d matching items, more than the maximum %d", matches, *s.maxContains)
+		}
+	}
+
+	if s.unevaluatedItems != nil && !ev.allItems {
+		for i, item := range arr {
+			if ev.items[i] {
+				continue
+			}
+
+			vs, _ := s.unevaluatedItems.validate(item, path+"/"+strconv.Itoa(i), depth+1)
+			violations = append(violations, vs...)
+			ev.items[i] = true
+		}
+	}
+
+	return violations
+}
+
+func (s *schema) validateObject(obj map[string]any, path string, depth int, ev *evaluated) []Violation {
+	var violations []Violation
+
+	fail := func(keyword, format string, args ...any) {
+		violations = append(violations, Violation{path, keyword, fmt.Sprintf(format, args...)})
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	if s.maxProperties != nil && len(obj) > *s.maxProperties {
+		fail("maxProperties", "object has %d properties, more than the maximum %d", len(obj), *s.maxProperties)
+	}
+
+	if s.minProperties != nil && len(obj) < *s.minProperties {
+		fail("minProperties", "object has %d properties, less than the minimum %d", len(obj), *s.minProperties)
+	}
+
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			fail("required", "missing required property %q", name)
+		}
+	}
+
+	for _, k := range keys {
+		for _, name := range s.dependentRequired[k] {
+			if _, ok := obj[name]; !ok {
+				fail("dependentRequired", "property %q is required when %q is present", name, k)
+			}
+		}
+	}
+
+	if s.propertyNames != nil {
+		for _, k := range keys {
+			if vs, _ := s.propertyNames.validate(k, path+"/"+escape(k), depth+1); len(vs) > 0 {
+				fail("propertyNames", "property name %q is not valid", k)
+			}
+		}
+	}
+
+	for _, k := range s.propertyOrder {
+		value, ok := obj[k]
+		if !ok {
+			continue
+		}
+
+		vs, _ := s.properties[k].validate(value, path+"/"+escape(k), depth+1)
+		violations = append(violations, vs...)
+		ev.props[k] = true
+	}
+
+	matchedPattern := make(map[string]bool)
+
+	for _, p := range s.patternProperties {
+		for _, k := range keys {
+			if !p.pattern.MatchString(k) {
+				continue
+			}
+
+			vs, _ := p.schema.validate(obj[k], path+"/"+escape(k), depth+1)
+			violations = append(violations, vs...)
+			ev.props[k] = true
+			matchedPattern[k] = true
+		}
+	}
+
+	if s.additionalProperties != nil {
+		for _, k := range keys {
+			if _, ok := s.properties[k]; ok || matchedPattern[k] {
+				continue
+			}
+
+			violations = append(violations, validateProperty(s.additionalProperties, "additionalProperties", obj[k], path, k, depth)...)
+			ev.props[k] = true
+		}
+	}
+
+	for _, k := range keys {
+		sub, ok := s.dependentSchemas[k]
+		if !ok {
+			continue
+		}
+
+		vs, subEv := sub.validate(obj, path, depth+1)
+		violations = append(violations, vs...)
+
+		if len(vs) == 0 {
+			ev.merge(subEv)
+		}
+	}
+
+	if s.unevaluatedProperties != nil {
+		for _, k := range keys {
+			if ev.props[k] {
+				continue
+			}
+
+			violations = append(violations, validateProperty(s.unevaluatedProperties, "unevaluatedProperties", obj[k], path, k, depth)...)
+			ev.props[k] = true
+		}
+	}
+
+	return violations
+}
+
+// validateProperty validates properties that are not declared in the schema,
+// describing them as not allowed when the schema is false.
+func validateProperty(s *schema, keyword string, value any, path, name string, depth int) []Violation {
+	p := path + "/" + escape(name)
+
+	if s.always != nil && !*s.always {
+		return []Violation{{p, keyword, fmt.Sprintf("property %q is not allowed", name)}}
+	}
+
+	vs, _ := s.validate(value, p, depth+1)
+
+	return vs
+}
+
+func hasType(v any, types []string) bool {
+	actual := typeOf(v)
+
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func typeOf(v any) string {
+	switch e := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if e == math.Trunc(e) && !math.IsInf(e, 0) {
+			return "integer"
+		}
+
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+
+	return fmt.Sprintf("%T", v)
+}
+
+func equal(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func format(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(b)
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
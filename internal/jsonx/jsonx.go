@@ -3,126 +3,52 @@ package jsonx
 
 import (
 	"errors"
-	"fmt"
-	"reflect"
-	"regexp"
-	"strconv"
-	"strings"
 )
 
-var (
-	fieldRegExp = regexp.MustCompile(`(\w+)\[(\d+)](.*)`)
-	idxRegExp   = regexp.MustCompile(`^\[(\d+)](.*)`)
+// ErrFieldNotFound is thrown when a JSON path is invalid.
+var ErrFieldNotFound = errors.New("could not find a field using provided json path")
 
-	// ErrFieldNotFound is thrown when a JSON path is invalid.
-	ErrFieldNotFound = errors.New("could not find a field using provided json path")
-)
-
-// Reach returns the field with the given path from the given json data
+// Reach returns the field with the given path from the given json data.
+// Paths are JSONPath expressions. See Compile for the supported syntax.
 // Example:
 //
 //	data := map[string]any{"address": map[string]any{"street": "somewhere"}}
 //	Reach("address.street", data)
 //	will return "somewhere"
 //
-// For arrays, use the notation "field[index]" to get a specific index
+// For arrays, use the notation "field[index]" to get a specific index.
+// Paths that can select multiple nodes, like "items[*].id", return a []any with all matches, which may be empty.
+// Paths that select a single node return ErrFieldNotFound when it doesn't exist.
 func Reach(path string, data any) (any, error) {
-	var dataType = reflect.TypeOf(data).Kind()
-	var hasBracket = strings.HasPrefix(path, "[")
-
-	switch dataType {
-	case reflect.Map:
-		if hasBracket {
-			return nil,
-				fmt.Errorf("json path starts with an index pattern [n] when the json is actually an object")
-		}
-
-		chain := strings.TrimPrefix(path, ".")
-
-		if matches := fieldRegExp.FindAllStringSubmatch(chain, -1); len(matches) > 0 {
-			values := matches[0]
-			idx, _ := strconv.Atoi(values[2])
-
-			field := data.(map[string]any)[values[1]].([]any)
-			size := len(field)
-
-			if idx > size-1 {
-				return nil, ErrFieldNotFound
-			}
-
-			entry := field[idx]
-			next := values[3]
-
-			if next != "" {
-				return Reach(next, entry)
-			}
-
-			if entry == nil {
-				if len(field) <= (idx + 1) {
-					return nil, nil
-				}
-
-				return nil, ErrFieldNotFound
-			}
-
-			return entry, nil
-		}
-
-		parts := strings.Split(chain, ".")
-		s := len(parts)
-
-		if s == 0 {
-			return nil, fmt.Errorf("invalid json path %s", chain)
-		}
-
-		if s == 1 {
-			val, ok := data.(map[string]any)[parts[0]]
-			if !ok {
-				return nil, ErrFieldNotFound
-			}
-
-			return val, nil
-		}
-
-		ch := strings.Join(parts[1:], ".")
-		val, ok := data.(map[string]any)[parts[0]]
-		if !ok {
-			return nil, ErrFieldNotFound
-		}
-
-		return Reach(ch, val)
-
-	case reflect.Slice:
-		if !hasBracket {
-			return nil,
-				fmt.Errorf("json is an array but the json path does not start with an index pattern []")
-		}
-
-		if matches := idxRegExp.FindAllStringSubmatch(path, -1); len(matches) > 0 {
-			values := matches[0]
-			idx, _ := strconv.Atoi(values[1])
+	if data == nil {
+		return nil, ErrFieldNotFound
+	}
 
-			d := data.([]any)
-			size := len(d)
+	p, err := Compile(path)
+	if err != nil {
+		return nil, err
+	}
 
-			if idx > size-1 {
-				return nil, ErrFieldNotFound
-			}
+	nodes := p.Find(data)
 
-			next := values[2]
-			field := data.([]any)[idx]
+	if !p.Singular() {
+		return nodes, nil
+	}
 
-			if next == "" {
-				if field == nil && len(data.([]any)) < (idx+1) {
-					return nil, ErrFieldNotFound
-				}
+	if len(nodes) == 0 {
+		return nil, ErrFieldNotFound
+	}
 
-				return field, nil
-			}
+	return nodes[0], nil
+}
 
-			return Reach(next, field)
-		}
+// FindAll returns all nodes selected by the given path.
+// Unlike Reach, it always returns a list, even for paths that select a single node.
+func FindAll(path string, data any) ([]any, error) {
+	p, err := Compile(path)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, ErrFieldNotFound
+	return p.Find(data), nil
 }
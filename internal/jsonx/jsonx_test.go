@@ -60,7 +60,11 @@ func TestArray(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Nil(t, nilObj)
 
-	noIdx, err := Reach("[-1]", data)
+	last, err := Reach("[-1]", data)
+	assert.Nil(t, err)
+	assert.Equal(t, data[0], last)
+
+	noIdx, err := Reach("[-2]", data)
 	assert.Nil(t, noIdx)
 	assert.NotNil(t, err)
 
@@ -168,4 +172,9 @@ func TestObject(t *testing.T) {
 	no, err := Reach("not_present", mapped)
 	assert.Nil(t, no)
 	assert.NotNil(t, err)
+
+	// nil data
+	no, err = Reach("name", nil)
+	assert.Nil(t, no)
+	assert.Equal(t, ErrFieldNotFound, err)
 }
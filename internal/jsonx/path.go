@@ -0,0 +1,1021 @@
+package jsonx
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Path is a compiled JSONPath expression.
+type Path struct {
+	raw      string
+	segments []*segment
+}
+
+type segment struct {
+	recursive bool
+	selectors []selector
+}
+
+type selector interface {
+	apply(node, root any, out []any) []any
+}
+
+type (
+	nameSelector     struct{ name string }
+	wildcardSelector struct{}
+	indexSelector    struct{ index int }
+	sliceSelector    struct{ start, end, step *int }
+	filterSelector   struct{ expr filterExpr }
+)
+
+// Compile parses a JSONPath expression.
+// Besides the standard syntax, the root identifier "$" is optional and the first member name can be written without
+// a leading dot, so "address.city", "$.address.city" and "$['address']['city']" are equivalent.
+// Supported selectors:
+//
+//	name, ['name'], ["name"]    object members. Quoted names can contain any character, including dots
+//	*, [*]                      all object members or array elements
+//	..name, ..*, ..[selectors]  recursive descent, applying the selectors to the node and all its descendants
+//	[1], [-1]                   array elements. Negative indexes count from the end
+//	[start:end:step]            array slices
+//	[0,2], ['a','b']            multiple selectors
+//	[?(@.status == 'open')]     filters, with the operators == != < <= > >= =~ ! && || and parentheses.
+//	                            @ refers to the current node and $ to the root. Paths without a comparison test
+//	                            for existence. =~ matches a regular expression, written like /^a/ or /^a/i
+//
+// Dotted paths that are not valid JSONPath expressions, and don't use brackets, wildcards or filters, are handled as
+// a sequence of literal member names, split by dots. This keeps member names like "@type", "$ref" or "first name"
+// working without bracket notation.
+func Compile(path string) (*Path, error) {
+	p := &parser{s: path}
+
+	segments, err := p.parsePath(true)
+	if err == nil && p.pos < len(p.s) {
+		err = fmt.Errorf("unexpected character %q at %d", p.s[p.pos], p.pos)
+	}
+
+	if err != nil {
+		if literal, ok := literalPath(path); ok {
+			return literal, nil
+		}
+
+		return nil, fmt.Errorf("invalid json path %s: %w", path, err)
+	}
+
+	return &Path{raw: path, segments: segments}, nil
+}
+
+// literalPath returns a Path of member names, split by dots, for paths without JSONPath selectors.
+func literalPath(path string) (*Path, bool) {
+	if path == "" || strings.ContainsAny(path, "[]*?") {
+		return nil, false
+	}
+
+	names := strings.Split(path, ".")
+	segments := make([]*segment, len(names))
+
+	for i, name := range names {
+		if name == "" {
+			return nil, false
+		}
+
+		segments[i] = &segment{selectors: []selector{&nameSelector{name: name}}}
+	}
+
+	return &Path{raw: path, segments: segments}, true
+}
+
+// MustCompile is like Compile but panics if the expression is invalid.
+func MustCompile(path string) *Path {
+	p, err := Compile(path)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// String returns the source expression.
+func (p *Path) String() string {
+	return p.raw
+}
+
+// Singular returns true when the path selects at most one node, using only member names and indexes.
+func (p *Path) Singular() bool {
+	for _, seg := range p.segments {
+		if seg.recursive || len(seg.selectors) != 1 {
+			return false
+		}
+
+		switch seg.selectors[0].(type) {
+		case *nameSelector, *indexSelector:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// Find returns all nodes selected by the path, in document order. Object members are visited in key order.
+func (p *Path) Find(data any) []any {
+	return p.find(data, data)
+}
+
+func (p *Path) find(node, root any) []any {
+	nodes := []any{node}
+
+	for _, seg := range p.segments {
+		var next []any
+
+		for _, n := range nodes {
+			candidates := []any{n}
+			if seg.recursive {
+				candidates = descendantsOrSelf(n, nil)
+			}
+
+			for _, c := range candidates {
+				for _, sel := range seg.selectors {
+					next = sel.apply(c, root, next)
+				}
+			}
+		}
+
+		nodes = next
+	}
+
+	if nodes == nil {
+		return make([]any, 0)
+	}
+
+	return nodes
+}
+
+func (s *nameSelector) apply(node, _ any, out []any) []any {
+	if v, ok := member(node, s.name); ok {
+		out = append(out, v)
+	}
+
+	return out
+}
+
+func (s *wildcardSelector) apply(node, _ any, out []any) []any {
+	return append(out, children(node)...)
+}
+
+func (s *indexSelector) apply(node, _ any, out []any) []any {
+	items, ok := elements(node)
+	if !ok {
+		return out
+	}
+
+	i := s.index
+	if i < 0 {
+		i += len(items)
+	}
+
+	if i < 0 || i >= len(items) {
+		return out
+	}
+
+	return append(out, items[i])
+}
+
+func (s *sliceSelector) apply(node, _ any, out []any) []any {
+	items, ok := elements(node)
+	if !ok {
+		return out
+	}
+
+	n := len(items)
+	step := 1
+	if s.step != nil {
+		step = *s.step
+	}
+
+	if step == 0 {
+		return out
+	}
+
+	normalize := func(i *int, def, lo, hi int) int {
+		if i == nil {
+			return def
+		}
+
+		v := *i
+		if v < 0 {
+			v += n
+		}
+
+		if v < lo {
+			return lo
+		} else if v > hi {
+			return hi
+		}
+
+		return v
+	}
+
+	if step > 0 {
+		lower := normalize(s.start, 0, 0, n)
+		upper := normalize(s.end, n, 0, n)
+
+		for i := lower; i < upper; i += step {
+			out = append(out, items[i])
+		}
+	} else {
+		upper := normalize(s.start, n-1, -1, n-1)
+		lower := normalize(s.end, -1, -1, n-1)
+
+		for i := upper; lower < i; i += step {
+			out = append(out, items[i])
+		}
+	}
+
+	return out
+}
+
+func (s *filterSelector) apply(node, root any, out []any) []any {
+	for _, child := range children(node) {
+		if s.expr.eval(child, root) {
+			out = append(out, child)
+		}
+	}
+
+	return out
+}
+
+// --
+// Filter Expressions
+// --
+
+type filterExpr interface {
+	eval(current, root any) bool
+}
+
+type (
+	orExpr      struct{ left, right filterExpr }
+	andExpr     struct{ left, right filterExpr }
+	notExpr     struct{ expr filterExpr }
+	existsExpr  struct{ path *pathOperand }
+	compareExpr struct {
+		op          string
+		left, right operand
+	}
+	regexExpr struct {
+		left operand
+		re   *regexp.Regexp
+	}
+)
+
+func (e *orExpr) eval(current, root any) bool {
+	return e.left.eval(current, root) || e.right.eval(current, root)
+}
+
+func (e *andExpr) eval(current, root any) bool {
+	return e.left.eval(current, root) && e.right.eval(current, root)
+}
+
+func (e *notExpr) eval(current, root any) bool {
+	return !e.expr.eval(current, root)
+}
+
+func (e *existsExpr) eval(current, root any) bool {
+	return len(e.path.nodes(current, root)) > 0
+}
+
+func (e *compareExpr) eval(current, root any) bool {
+	l, lok := e.left.value(current, root)
+	r, rok := e.right.value(current, root)
+
+	switch e.op {
+	case "==":
+		return lok == rok && (!lok || equal(l, r))
+	case "!=":
+		return !(lok == rok && (!lok || equal(l, r)))
+	}
+
+	if !lok || !rok {
+		return false
+	}
+
+	var c int
+
+	if ln, ok := number(l); ok {
+		rn, ok := number(r)
+		if !ok {
+			return false
+		}
+
+		c = compareFloat(ln, rn)
+	} else if ls, ok := l.(string); ok {
+		rs, ok := r.(string)
+		if !ok {
+			return false
+		}
+
+		c = strings.Compare(ls, rs)
+	} else {
+		return false
+	}
+
+	switch e.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+func (e *regexExpr) eval(current, root any) bool {
+	v, ok := e.left.value(current, root)
+	if !ok {
+		return false
+	}
+
+	s, ok := v.(string)
+
+	return ok && e.re.MatchString(s)
+}
+
+// operand is a filter comparison operand. value returns false when a path operand doesn't select exactly one node.
+type operand interface {
+	value(current, root any) (any, bool)
+}
+
+type (
+	literalOperand struct{ v any }
+	pathOperand    struct {
+		relative bool
+		path     *Path
+	}
+)
+
+func (o *literalOperand) value(_, _ any) (any, bool) {
+	return o.v, true
+}
+
+func (o *pathOperand) nodes(current, root any) []any {
+	if o.relative {
+		return o.path.find(current, root)
+	}
+
+	return o.path.find(root, root)
+}
+
+func (o *pathOperand) value(current, root any) (any, bool) {
+	nodes := o.nodes(current, root)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+
+	return nodes[0], true
+}
+
+// --
+// Parser
+// --
+
+type parser struct {
+	s   string
+	pos int
+}
+
+// parsePath parses the path segments. Top level paths may omit the root identifier and start with a member name.
+// Paths inside filters stop at the first character that doesn't start a segment.
+func (p *parser) parsePath(top bool) ([]*segment, error) {
+	segments := make([]*segment, 0)
+
+	if top {
+		if p.peek() == '$' {
+			p.pos++
+		} else if p.pos < len(p.s) && p.peek() != '.' && p.peek() != '[' {
+			seg, err := p.parseDotSelector()
+			if err != nil {
+				return nil, err
+			}
+
+			segments = append(segments, seg)
+		}
+	}
+
+	for p.pos < len(p.s) {
+		switch {
+		case strings.HasPrefix(p.s[p.pos:], ".."):
+			p.pos += 2
+
+			var seg *segment
+			var err error
+
+			if p.peek() == '[' {
+				seg, err = p.parseBracket()
+			} else {
+				seg, err = p.parseDotSelector()
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			seg.recursive = true
+			segments = append(segments, seg)
+
+		case p.peek() == '.':
+			p.pos++
+
+			seg, err := p.parseDotSelector()
+			if err != nil {
+				return nil, err
+			}
+
+			segments = append(segments, seg)
+
+		case p.peek() == '[':
+			seg, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+
+			segments = append(segments, seg)
+
+		default:
+			if top {
+				return nil, fmt.Errorf("unexpected character %q at %d", p.s[p.pos], p.pos)
+			}
+
+			return segments, nil
+		}
+	}
+
+	return segments, nil
+}
+
+// parseDotSelector parses a member name or a wildcard written in dot notation.
+func (p *parser) parseDotSelector() (*segment, error) {
+	if p.peek() == '*' {
+		p.pos++
+		return &segment{selectors: []selector{&wildcardSelector{}}}, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		if !isNameChar(r) {
+			break
+		}
+
+		p.pos += size
+	}
+
+	if start == p.pos {
+		return nil, fmt.Errorf("expected a member name at %d", start)
+	}
+
+	return &segment{selectors: []selector{&nameSelector{name: p.s[start:p.pos]}}}, nil
+}
+
+// parseBracket parses a comma separated list of selectors between brackets.
+func (p *parser) parseBracket() (*segment, error) {
+	p.pos++
+
+	seg := &segment{}
+
+	for {
+		p.skipSpaces()
+
+		sel, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+
+		seg.selectors = append(seg.selectors, sel)
+
+		p.skipSpaces()
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return seg, nil
+		default:
+			return nil, fmt.Errorf("expected , or ] at %d", p.pos)
+		}
+	}
+}
+
+func (p *parser) parseSelector() (selector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+
+		return &nameSelector{name: name}, nil
+
+	case c == '*':
+		p.pos++
+		return &wildcardSelector{}, nil
+
+	case c == '?':
+		p.pos++
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		return &filterSelector{expr: expr}, nil
+
+	case c == ':' || c == '-' || isDigit(c):
+		return p.parseIndexOrSlice()
+	}
+
+	return nil, fmt.Errorf("invalid selector at %d", p.pos)
+}
+
+func (p *parser) parseIndexOrSlice() (selector, error) {
+	var parts [3]*int
+	colons := 0
+
+	for {
+		p.skipSpaces()
+
+		if c := p.peek(); c == '-' || isDigit(c) {
+			n, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+
+			parts[colons] = &n
+		}
+
+		p.skipSpaces()
+
+		if p.peek() != ':' || colons == 2 {
+			break
+		}
+
+		p.pos++
+		colons++
+	}
+
+	if colons == 0 {
+		if parts[0] == nil {
+			return nil, fmt.Errorf("expected an index at %d", p.pos)
+		}
+
+		return &indexSelector{index: *parts[0]}, nil
+	}
+
+	return &sliceSelector{start: parts[0], end: parts[1], step: parts[2]}, nil
+}
+
+func (p *parser) parseInt() (int, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+
+	for isDigit(p.peek()) {
+		p.pos++
+	}
+
+	n, err := strconv.Atoi(p.s[start:p.pos])
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer %q at %d", p.s[start:p.pos], start)
+	}
+
+	return n, nil
+}
+
+// parseString parses a single or double-quoted string, supporting backslash escapes.
+func (p *parser) parseString() (string, error) {
+	quote := p.s[p.pos]
+	start := p.pos
+	p.pos++
+
+	b := strings.Builder{}
+
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+
+		switch c {
+		case quote:
+			p.pos++
+			return b.String(), nil
+		case '\\':
+			if p.pos+1 >= len(p.s) {
+				return "", fmt.Errorf("unterminated string at %d", start)
+			}
+
+			p.pos++
+
+			switch e := p.s[p.pos]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+
+		p.pos++
+	}
+
+	return "", fmt.Errorf("unterminated string at %d", start)
+}
+
+func (p *parser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.consume("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &orExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.consume("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &andExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (filterExpr, error) {
+	p.skipSpaces()
+
+	switch p.peek() {
+	case '!':
+		p.pos++
+
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &notExpr{expr: expr}, nil
+
+	case '(':
+		p.pos++
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.consume(")") {
+			return nil, fmt.Errorf("expected ) at %d", p.pos)
+		}
+
+		return expr, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (filterExpr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.consume("=~") {
+		p.skipSpaces()
+
+		re, err := p.parseRegex()
+		if err != nil {
+			return nil, err
+		}
+
+		return &regexExpr{left: left, re: re}, nil
+	}
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.consume(op) {
+			continue
+		}
+
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		return &compareExpr{op: op, left: left, right: right}, nil
+	}
+
+	path, ok := left.(*pathOperand)
+	if !ok {
+		return nil, fmt.Errorf("expected a comparison at %d", p.pos)
+	}
+
+	return &existsExpr{path: path}, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	p.skipSpaces()
+
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+
+		segments, err := p.parsePath(false)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pathOperand{relative: c == '@', path: &Path{segments: segments}}, nil
+
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+
+		return &literalOperand{v: s}, nil
+
+	case c == '-' || isDigit(c):
+		start := p.pos
+		p.pos++
+
+		for isDigit(p.peek()) || strings.ContainsRune(".eE+-", rune(p.peek())) {
+			p.pos++
+		}
+
+		n, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at %d", p.s[start:p.pos], start)
+		}
+
+		return &literalOperand{v: n}, nil
+	}
+
+	for keyword, v := range map[string]any{"true": true, "false": false, "null": nil} {
+		if strings.HasPrefix(p.s[p.pos:], keyword) {
+			p.pos += len(keyword)
+			return &literalOperand{v: v}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid filter operand at %d", p.pos)
+}
+
+// parseRegex parses a regular expression written like /expr/ or /expr/i. Quoted strings are also accepted.
+func (p *parser) parseRegex() (*regexp.Regexp, error) {
+	var expr string
+
+	switch p.peek() {
+	case '\'', '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = s
+
+	case '/':
+		start := p.pos
+		p.pos++
+
+		b := strings.Builder{}
+		for p.pos < len(p.s) && p.s[p.pos] != '/' {
+			if p.s[p.pos] == '\\' && p.pos+1 < len(p.s) && p.s[p.pos+1] == '/' {
+				p.pos++
+			}
+
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		}
+
+		if p.pos >= len(p.s) {
+			return nil, fmt.Errorf("unterminated regular expression at %d", start)
+		}
+
+		p.pos++
+		expr = b.String()
+
+		if p.peek() == 'i' {
+			p.pos++
+			expr = "(?i)" + expr
+		}
+
+	default:
+		return nil, fmt.Errorf("expected a regular expression at %d", p.pos)
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %s: %w", expr, err)
+	}
+
+	return re, nil
+}
+
+func (p *parser) consume(token string) bool {
+	p.skipSpaces()
+
+	if strings.HasPrefix(p.s[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t' || p.s[p.pos] == '\n' || p.s[p.pos] == '\r') {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() byte {
+	if p.pos >= len(p.s) {
+		return 0
+	}
+
+	return p.s[p.pos]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameChar(r rune) bool {
+	return r == '_' || r == '-' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r) || r >= 0x80
+}
+
+// --
+// Values
+// --
+
+// member returns the object member with the given name.
+func member(node any, name string) (any, bool) {
+	switch e := node.(type) {
+	case map[string]any:
+		v, ok := e[name]
+		return v, ok
+	case nil:
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(node)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+
+	v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+	if !v.IsValid() {
+		return nil, false
+	}
+
+	return v.Interface(), true
+}
+
+// elements returns the array elements.
+func elements(node any) ([]any, bool) {
+	switch e := node.(type) {
+	case []any:
+		return e, true
+	case nil, string:
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(node)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+
+	return items, true
+}
+
+// children returns the array elements or the object member values, in key order.
+func children(node any) []any {
+	if items, ok := elements(node); ok {
+		return items
+	}
+
+	if m, ok := node.(map[string]any); ok {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		values := make([]any, len(keys))
+		for i, k := range keys {
+			values[i] = m[k]
+		}
+
+		return values
+	}
+
+	rv := reflect.ValueOf(node)
+	if node == nil || rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	values := make([]any, len(keys))
+	for i, k := range keys {
+		values[i] = rv.MapIndex(k).Interface()
+	}
+
+	return values
+}
+
+// descendantsOrSelf returns the node followed by all its descendants, in document order.
+func descendantsOrSelf(node any, out []any) []any {
+	out = append(out, node)
+
+	for _, child := range children(node) {
+		out = descendantsOrSelf(child, out)
+	}
+
+	return out
+}
+
+func equal(a, b any) bool {
+	if an, ok := number(a); ok {
+		bn, ok := number(b)
+		return ok && an == bn
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func number(v any) (float64, bool) {
+	switch e := v.(type) {
+	case float64:
+		return e, true
+	case nil, string, bool:
+		return 0, false
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+
+	return 0, false
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,173 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const _store = `{
+	"store": {
+		"book": [
+			{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+			{"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+			{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+			{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+		],
+		"bicycle": {"color": "red", "price": 399}
+	},
+	"expensive": 10,
+	"key.with.dots": "dots",
+	"key with 'quotes'": "quotes"
+}`
+
+func TestPath_Find(t *testing.T) {
+	var data any
+	if err := json.Unmarshal([]byte(_store), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		path     string
+		expected []any
+	}{
+		{"$.store.bicycle.color", []any{"red"}},
+		{"store.bicycle.color", []any{"red"}},
+		{"$['store']['bicycle'][\"color\"]", []any{"red"}},
+		{"$", []any{data}},
+		{"['key.with.dots']", []any{"dots"}},
+		{`$['key with \'quotes\'']`, []any{"quotes"}},
+		{"store.book[*].author", []any{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{"store.book.*.price", []any{8.95, 12.99, 8.99, 22.99}},
+		{"$..author", []any{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{"$.store.*", nil},
+		{"$.store..price", []any{399.0, 8.95, 12.99, 8.99, 22.99}},
+		{"$..book[2].title", []any{"Moby Dick"}},
+		{"$..book[-1].title", []any{"The Lord of the Rings"}},
+		{"$..book[0,1].title", []any{"Sayings of the Century", "Sword of Honour"}},
+		{"$..book[:2].title", []any{"Sayings of the Century", "Sword of Honour"}},
+		{"$..book[1:3].title", []any{"Sword of Honour", "Moby Dick"}},
+		{"$..book[-2:].title", []any{"Moby Dick", "The Lord of the Rings"}},
+		{"$..book[::2].title", []any{"Sayings of the Century", "Moby Dick"}},
+		{"$..book[::-1].price", []any{22.99, 8.99, 12.99, 8.95}},
+		{"$..book[3:1:-1].price", []any{22.99, 8.99}},
+		{"$..book[?(@.isbn)].title", []any{"Moby Dick", "The Lord of the Rings"}},
+		{"$..book[?(!@.isbn)].title", []any{"Sayings of the Century", "Sword of Honour"}},
+		{"$..book[?(@.price < 10)].price", []any{8.95, 8.99}},
+		{"$..book[?(@.price <= $.expensive)].price", []any{8.95, 8.99}},
+		{"$..book[?(@.price > 10 && @.category == 'fiction')].title", []any{"Sword of Honour", "The Lord of the Rings"}},
+		{"$..book[?(@.price > 20 || @.category == \"reference\")].title", []any{"Sayings of the Century", "The Lord of the Rings"}},
+		{"$..book[?(@.category != 'fiction')].title", []any{"Sayings of the Century"}},
+		{"$..book[?((@.price < 9) && !(@.category == 'reference'))].title", []any{"Moby Dick"}},
+		{"$..book[?(@.author =~ /tolkien/i)].title", []any{"The Lord of the Rings"}},
+		{"$..book[?@.title =~ '^S'].title", []any{"Sayings of the Century", "Sword of Honour"}},
+		{"$..book[?(@.title > 'T')].title", []any{"The Lord of the Rings"}},
+		{"$..book[?(@.missing == null)].title", []any{}},
+		{"$..[?(@.color == 'red')].price", []any{399.0}},
+		{"$.store.book[10]", []any{}},
+		{"$.missing", []any{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			p, err := Compile(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res := p.Find(data)
+
+			if tc.path == "$.store.*" {
+				assert.Len(t, res, 2)
+				return
+			}
+
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestPath_Singular(t *testing.T) {
+	assert.True(t, MustCompile("a.b[0]").Singular())
+	assert.True(t, MustCompile("$['a'][-1]").Singular())
+	assert.False(t, MustCompile("a[*]").Singular())
+	assert.False(t, MustCompile("a..b").Singular())
+	assert.False(t, MustCompile("a[0,1]").Singular())
+	assert.False(t, MustCompile("a[0:1]").Singular())
+	assert.False(t, MustCompile("a[?(@.b)]").Singular())
+}
+
+func TestPath_GoValues(t *testing.T) {
+	data := map[string]any{
+		"ids":  []int{1, 2, 3},
+		"tags": map[string][]string{"a": {"x"}},
+	}
+
+	assert.Equal(t, []any{2, 3}, MustCompile("ids[?(@ > 1)]").Find(data))
+	assert.Equal(t, []any{"x"}, MustCompile("tags.a[0]").Find(data))
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, path := range []string{
+		"a.", "a..", "a[", "a[abc]", "a['b'", "a[?(@.b ==)]", "a[?(@.b =~ /(/)]", "a[?('b')]", "a[1:2:3:4]", "a b[0",
+	} {
+		t.Run(path, func(t *testing.T) {
+			_, err := Compile(path)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestCompile_LiteralMembers(t *testing.T) {
+	data := map[string]any{
+		"@type":      "Person",
+		"$ref":       "#/defs/user",
+		"first name": "dev",
+		"$$":         "dollars",
+		"meta":       map[string]any{"@id": "urn:1"},
+	}
+
+	testCases := []struct {
+		path     string
+		expected any
+	}{
+		{"@type", "Person"},
+		{"$ref", "#/defs/user"},
+		{"first name", "dev"},
+		{"$$", "dollars"},
+		{"meta.@id", "urn:1"},
+		{"['@type']", "Person"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			v, err := Reach(tc.path, data)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, v)
+		})
+	}
+}
+
+func TestReach_Lists(t *testing.T) {
+	data := map[string]any{"items": []any{
+		map[string]any{"id": 1.0, "status": "open"},
+		map[string]any{"id": 2.0, "status": "closed"},
+	}}
+
+	ids, err := Reach("items[*].id", data)
+	assert.Nil(t, err)
+	assert.Equal(t, []any{1.0, 2.0}, ids)
+
+	none, err := Reach("items[?(@.status == 'draft')]", data)
+	assert.Nil(t, err)
+	assert.Equal(t, []any{}, none)
+
+	all, err := FindAll("items[0].id", data)
+	assert.Nil(t, err)
+	assert.Equal(t, []any{1.0}, all)
+
+	_, err = FindAll("items[", data)
+	assert.NotNil(t, err)
+}
@@ -3,8 +3,13 @@ package mimetypes
 
 // Common mime types.
 const (
-	JSON           = "application/json"
-	TextPlain      = "text/plain"
-	TextHTML       = "text/html"
-	FormURLEncoded = "application/x-www-form-urlencoded"
+	JSON              = "application/json"
+	TextPlain         = "text/plain"
+	TextHTML          = "text/html"
+	XML               = "application/xml"
+	TextXML           = "text/xml"
+	FormURLEncoded    = "application/x-www-form-urlencoded"
+	MultipartFormData = "multipart/form-data"
+	EventStream       = "text/event-stream"
+	GRPC              = "application/grpc"
 )
@@ -0,0 +1,166 @@
+// Package xmlx implements a minimal XML document model and an XPath 1.0 subset, used to match XML request bodies.
+// Element and attribute names are matched by their local names, so namespace prefixes are optional in expressions.
+package xmlx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// NodeType is the type of Node.
+type NodeType int
+
+// Node types.
+const (
+	DocumentNode NodeType = iota
+	ElementNode
+	AttributeNode
+	TextNode
+)
+
+// Node is a node of a parsed XML document.
+type Node struct {
+	// Type is the node type.
+	Type NodeType
+
+	// Name is the element or attribute name. Name.Space holds the namespace URI.
+	Name xml.Name
+
+	// Value is the attribute value or the text content of text nodes.
+	Value string
+
+	// Attr are the element attributes, excluding namespace declarations.
+	Attr []*Node
+
+	// Children are the child elements and text nodes.
+	Children []*Node
+
+	// Parent is the parent node. It is nil for the document node.
+	Parent *Node
+}
+
+// Parse parses the given XML content, returning its document node.
+func Parse(data []byte) (*Node, error) {
+	doc := &Node{Type: DocumentNode}
+	current := doc
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		tok, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			el := &Node{Type: ElementNode, Name: t.Name, Parent: current}
+
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+					continue
+				}
+
+				el.Attr = append(el.Attr, &Node{Type: AttributeNode, Name: attr.Name, Value: attr.Value, Parent: el})
+			}
+
+			current.Children = append(current.Children, el)
+			current = el
+
+		case xml.EndElement:
+			current = current.Parent
+
+		case xml.CharData:
+			if current == doc || strings.TrimSpace(string(t)) == "" {
+				continue
+			}
+
+			current.Children = append(current.Children, &Node{Type: TextNode, Value: string(t), Parent: current})
+		}
+	}
+
+	if doc.Root() == nil {
+		return nil, errors.New("xml document has no root element")
+	}
+
+	return doc, nil
+}
+
+// Root returns the document root element.
+func (n *Node) Root() *Node {
+	doc := n
+	for doc.Parent != nil {
+		doc = doc.Parent
+	}
+
+	for _, child := range doc.Children {
+		if child.Type == ElementNode {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// Elements returns the child elements.
+func (n *Node) Elements() []*Node {
+	elements := make([]*Node, 0, len(n.Children))
+	for _, child := range n.Children {
+		if child.Type == ElementNode {
+			elements = append(elements, child)
+		}
+	}
+
+	return elements
+}
+
+// Attribute returns the value of the attribute with the given local name.
+func (n *Node) Attribute(local string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Name.Local == local {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Text returns the node string value: the concatenation of all descendant text nodes for documents and elements,
+// and the value for attributes and text nodes.
+func (n *Node) Text() string {
+	if n.Type == AttributeNode || n.Type == TextNode {
+		return n.Value
+	}
+
+	b := strings.Builder{}
+	n.writeText(&b)
+
+	return b.String()
+}
+
+func (n *Node) writeText(b *strings.Builder) {
+	for _, child := range n.Children {
+		if child.Type == TextNode {
+			b.WriteString(child.Value)
+		} else {
+			child.writeText(b)
+		}
+	}
+}
+
+// descendantsOrSelf returns the node followed by all its descendant documents and elements, in document order.
+func (n *Node) descendantsOrSelf() []*Node {
+	nodes := []*Node{n}
+
+	for _, child := range n.Children {
+		if child.Type == ElementNode {
+			nodes = append(nodes, child.descendantsOrSelf()...)
+		}
+	}
+
+	return nodes
+}
@@ -0,0 +1,38 @@
+package xmlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := Parse([]byte(`<?xml version="1.0"?>
+		<!-- users -->
+		<ns:users xmlns:ns="urn:users" count="2">
+			<ns:user id="1"><name>dev</name></ns:user>
+			<ns:user id="2"><name><![CDATA[qa <team>]]></name></ns:user>
+		</ns:users>`))
+
+	assert.Nil(t, err)
+
+	root := doc.Root()
+	assert.Equal(t, "users", root.Name.Local)
+	assert.Equal(t, "urn:users", root.Name.Space)
+	assert.Len(t, root.Attr, 1)
+	assert.Len(t, root.Elements(), 2)
+
+	count, ok := root.Attribute("count")
+	assert.True(t, ok)
+	assert.Equal(t, "2", count)
+
+	assert.Equal(t, "devqa <team>", root.Text())
+	assert.Equal(t, "qa <team>", root.Elements()[1].Text())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, data := range []string{"", "text", "<a><b></a>", "<a>"} {
+		_, err := Parse([]byte(data))
+		assert.NotNil(t, err, data)
+	}
+}
@@ -0,0 +1,806 @@
+package xmlx
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// XPath is a compiled XPath expression.
+// It supports a subset of XPath 1.0: absolute and relative location paths, the abbreviated axes
+// ("//", ".", "..", "@"), wildcards, text() and node() tests, predicates with boolean, comparison and positional
+// expressions, unions and the functions last, position, count, contains, starts-with, ends-with, not, string,
+// string-length, normalize-space, number, local-name, name, true and false.
+type XPath struct {
+	src  string
+	expr expr
+}
+
+type (
+	expr interface {
+		eval(ctx evalContext) any
+	}
+
+	evalContext struct {
+		node *Node
+		pos  int
+		size int
+	}
+
+	nodeSet []*Node
+
+	literalExpr string
+
+	numberExpr float64
+
+	binaryExpr struct {
+		op   string
+		l, r expr
+	}
+
+	unionExpr struct {
+		l, r expr
+	}
+
+	funcExpr struct {
+		name string
+		args []expr
+	}
+
+	pathExpr struct {
+		absolute bool
+		steps    []*step
+	}
+
+	step struct {
+		kind       stepKind
+		deep       bool
+		name       string
+		predicates []expr
+	}
+
+	stepKind int
+
+	xpathToken struct {
+		kind  xpathTokenKind
+		value string
+	}
+
+	xpathTokenKind int
+
+	xpathParser struct {
+		tokens []xpathToken
+		pos    int
+	}
+)
+
+const (
+	stepChild stepKind = iota
+	stepAttribute
+	stepSelf
+	stepParent
+	stepText
+	stepNode
+)
+
+const (
+	xtName xpathTokenKind = iota
+	xtLiteral
+	xtNumber
+	xtOp
+	xtEOF
+)
+
+// Compile parses the given XPath expression.
+func Compile(src string) (*XPath, error) {
+	tokens, err := tokenizeXPath(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &xpathParser{tokens: tokens}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != xtEOF {
+		return nil, fmt.Errorf("xpath %s: unexpected token %q", src, p.peek().value)
+	}
+
+	return &XPath{src: src, expr: e}, nil
+}
+
+// MustCompile is like Compile but panics if the expression cannot be parsed.
+func MustCompile(src string) *XPath {
+	x, err := Compile(src)
+	if err != nil {
+		panic(err)
+	}
+
+	return x
+}
+
+// String returns the source expression.
+func (x *XPath) String() string {
+	return x.src
+}
+
+// Evaluate evaluates the expression against the given node.
+// The result is a []*Node for location paths, or a string, float64 or bool for other expressions.
+func (x *XPath) Evaluate(n *Node) any {
+	v := x.expr.eval(evalContext{node: n, pos: 1, size: 1})
+	if ns, ok := v.(nodeSet); ok {
+		return []*Node(ns)
+	}
+
+	return v
+}
+
+// Select returns the nodes selected by the expression.
+// It returns nil when the expression doesn't result in a node-set.
+func (x *XPath) Select(n *Node) []*Node {
+	nodes, _ := x.Evaluate(n).([]*Node)
+	return nodes
+}
+
+func (e literalExpr) eval(_ evalContext) any { return string(e) }
+
+func (e numberExpr) eval(_ evalContext) any { return float64(e) }
+
+func (e *unionExpr) eval(ctx evalContext) any {
+	l, _ := e.l.eval(ctx).(nodeSet)
+	r, _ := e.r.eval(ctx).(nodeSet)
+
+	return dedupe(append(append(nodeSet{}, l...), r...))
+}
+
+func (e *binaryExpr) eval(ctx evalContext) any {
+	switch e.op {
+	case "or":
+		return toBool(e.l.eval(ctx)) || toBool(e.r.eval(ctx))
+	case "and":
+		return toBool(e.l.eval(ctx)) && toBool(e.r.eval(ctx))
+	}
+
+	return compare(e.op, e.l.eval(ctx), e.r.eval(ctx))
+}
+
+func (e *pathExpr) eval(ctx evalContext) any {
+	current := nodeSet{ctx.node}
+
+	if e.absolute {
+		doc := ctx.node
+		for doc.Parent != nil {
+			doc = doc.Parent
+		}
+
+		current = nodeSet{doc}
+	}
+
+	for _, s := range e.steps {
+		next := make(nodeSet, 0)
+
+		for _, n := range current {
+			base := nodeSet{n}
+			if s.deep {
+				base = n.descendantsOrSelf()
+			}
+
+			for _, b := range base {
+				next = append(next, s.apply(b)...)
+			}
+		}
+
+		current = dedupe(next)
+	}
+
+	return current
+}
+
+func (s *step) apply(n *Node) nodeSet {
+	candidates := make(nodeSet, 0)
+
+	switch s.kind {
+	case stepSelf:
+		candidates = append(candidates, n)
+	case stepParent:
+		if n.Parent != nil {
+			candidates = append(candidates, n.Parent)
+		}
+	case stepAttribute:
+		for _, attr := range n.Attr {
+			if s.name == "*" || attr.Name.Local == s.name {
+				candidates = append(candidates, attr)
+			}
+		}
+	case stepText:
+		for _, child := range n.Children {
+			if child.Type == TextNode {
+				candidates = append(candidates, child)
+			}
+		}
+	case stepNode:
+		candidates = append(candidates, n.Children...)
+	default:
+		for _, child := range n.Children {
+			if child.Type == ElementNode && (s.name == "*" || child.Name.Local == s.name) {
+				candidates = append(candidates, child)
+			}
+		}
+	}
+
+	for _, predicate := range s.predicates {
+		filtered := make(nodeSet, 0, len(candidates))
+
+		for i, c := range candidates {
+			v := predicate.eval(evalContext{node: c, pos: i + 1, size: len(candidates)})
+
+			if num, ok := v.(float64); ok {
+				if num == float64(i+1) {
+					filtered = append(filtered, c)
+				}
+			} else if toBool(v) {
+				filtered = append(filtered, c)
+			}
+		}
+
+		candidates = filtered
+	}
+
+	return candidates
+}
+
+func (e *funcExpr) eval(ctx evalContext) any {
+	arg := func(i int) any {
+		if i < len(e.args) {
+			return e.args[i].eval(ctx)
+		}
+
+		return nodeSet{ctx.node}
+	}
+
+	switch e.name {
+	case "last":
+		return float64(ctx.size)
+	case "position":
+		return float64(ctx.pos)
+	case "count":
+		ns, _ := arg(0).(nodeSet)
+		return float64(len(ns))
+	case "contains":
+		return strings.Contains(toString(arg(0)), toString(arg(1)))
+	case "starts-with":
+		return strings.HasPrefix(toString(arg(0)), toString(arg(1)))
+	case "ends-with":
+		return strings.HasSuffix(toString(arg(0)), toString(arg(1)))
+	case "not":
+		return !toBool(arg(0))
+	case "string":
+		return toString(arg(0))
+	case "string-length":
+		return float64(len([]rune(toString(arg(0)))))
+	case "normalize-space":
+		return strings.Join(strings.Fields(toString(arg(0))), " ")
+	case "number":
+		return toNumber(arg(0))
+	case "local-name", "name":
+		ns, _ := arg(0).(nodeSet)
+		if len(ns) == 0 {
+			return ""
+		}
+
+		return ns[0].Name.Local
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	return nil
+}
+
+func compare(op string, l, r any) bool {
+	if ls, ok := l.(nodeSet); ok {
+		for _, n := range ls {
+			if compare(op, n.Text(), r) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if rs, ok := r.(nodeSet); ok {
+		for _, n := range rs {
+			if compare(op, l, n.Text()) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	switch op {
+	case "=", "!=":
+		var eq bool
+
+		_, lb := l.(bool)
+		_, rb := r.(bool)
+		_, ln := l.(float64)
+		_, rn := r.(float64)
+
+		switch {
+		case lb || rb:
+			eq = toBool(l) == toBool(r)
+		case ln || rn:
+			eq = toNumber(l) == toNumber(r)
+		default:
+			eq = toString(l) == toString(r)
+		}
+
+		if op == "=" {
+			return eq
+		}
+
+		return !eq
+	case "<":
+		return toNumber(l) < toNumber(r)
+	case "<=":
+		return toNumber(l) <= toNumber(r)
+	case ">":
+		return toNumber(l) > toNumber(r)
+	case ">=":
+		return toNumber(l) >= toNumber(r)
+	}
+
+	return false
+}
+
+func toString(v any) string {
+	switch e := v.(type) {
+	case nodeSet:
+		if len(e) == 0 {
+			return ""
+		}
+
+		return e[0].Text()
+	case string:
+		return e
+	case float64:
+		if e == math.Trunc(e) && !math.IsInf(e, 0) {
+			return strconv.FormatInt(int64(e), 10)
+		}
+
+		return strconv.FormatFloat(e, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(e)
+	}
+
+	return ""
+}
+
+func toNumber(v any) float64 {
+	switch e := v.(type) {
+	case float64:
+		return e
+	case bool:
+		if e {
+			return 1
+		}
+
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSpace(toString(v)), 64)
+	if err != nil {
+		return math.NaN()
+	}
+
+	return f
+}
+
+func toBool(v any) bool {
+	switch e := v.(type) {
+	case nodeSet:
+		return len(e) > 0
+	case string:
+		return e != ""
+	case float64:
+		return e != 0 && !math.IsNaN(e)
+	case bool:
+		return e
+	}
+
+	return false
+}
+
+func dedupe(nodes nodeSet) nodeSet {
+	seen := make(map[*Node]struct{}, len(nodes))
+	result := make(nodeSet, 0, len(nodes))
+
+	for _, n := range nodes {
+		if _, ok := seen[n]; ok {
+			continue
+		}
+
+		seen[n] = struct{}{}
+		result = append(result, n)
+	}
+
+	return result
+}
+
+func (p *xpathParser) peek() xpathToken {
+	return p.tokens[p.pos]
+}
+
+func (p *xpathParser) peekAt(offset int) xpathToken {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+
+	return p.tokens[p.pos+offset]
+}
+
+func (p *xpathParser) next() xpathToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != xtEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *xpathParser) isOp(values ...string) bool {
+	tok := p.peek()
+	if tok.kind != xtOp {
+		return false
+	}
+
+	for _, v := range values {
+		if tok.value == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *xpathParser) expectOp(value string) error {
+	if !p.isOp(value) {
+		return fmt.Errorf("xpath: expected %q. got %q", value, p.peek().value)
+	}
+
+	p.next()
+
+	return nil
+}
+
+func (p *xpathParser) parseOr() (expr, error) {
+	return p.parseBinary(func() (expr, error) { return p.parseAnd() }, "or")
+}
+
+func (p *xpathParser) parseAnd() (expr, error) {
+	return p.parseBinary(func() (expr, error) { return p.parseEquality() }, "and")
+}
+
+func (p *xpathParser) parseBinary(operand func() (expr, error), keyword string) (expr, error) {
+	l, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == xtName && p.peek().value == keyword {
+		p.next()
+
+		r, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		l = &binaryExpr{op: keyword, l: l, r: r}
+	}
+
+	return l, nil
+}
+
+func (p *xpathParser) parseEquality() (expr, error) {
+	l, err := p.parseRelational()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOp("=", "!=") {
+		op := p.next().value
+
+		r, err := p.parseRelational()
+		if err != nil {
+			return nil, err
+		}
+
+		l = &binaryExpr{op: op, l: l, r: r}
+	}
+
+	return l, nil
+}
+
+func (p *xpathParser) parseRelational() (expr, error) {
+	l, err := p.parseUnion()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOp("<", "<=", ">", ">=") {
+		op := p.next().value
+
+		r, err := p.parseUnion()
+		if err != nil {
+			return nil, err
+		}
+
+		l = &binaryExpr{op: op, l: l, r: r}
+	}
+
+	return l, nil
+}
+
+func (p *xpathParser) parseUnion() (expr, error) {
+	l, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOp("|") {
+		p.next()
+
+		r, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+
+		l = &unionExpr{l: l, r: r}
+	}
+
+	return l, nil
+}
+
+func (p *xpathParser) parsePrimary() (expr, error) {
+	tok := p.peek()
+
+	switch {
+	case tok.kind == xtLiteral:
+		p.next()
+		return literalExpr(tok.value), nil
+
+	case tok.kind == xtNumber:
+		p.next()
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("xpath: invalid number %s", tok.value)
+		}
+
+		return numberExpr(f), nil
+
+	case tok.kind == xtOp && tok.value == "(":
+		p.next()
+
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		return e, p.expectOp(")")
+
+	case tok.kind == xtName && tok.value != "text" && tok.value != "node" &&
+		p.peekAt(1).kind == xtOp && p.peekAt(1).value == "(":
+		return p.parseFunction()
+	}
+
+	return p.parsePath()
+}
+
+func (p *xpathParser) parseFunction() (expr, error) {
+	name := p.next().value
+	p.next()
+
+	f := &funcExpr{name: name, args: make([]expr, 0)}
+
+	switch name {
+	case "last", "position", "count", "contains", "starts-with", "ends-with", "not", "string", "string-length",
+		"normalize-space", "number", "local-name", "name", "true", "false":
+	default:
+		return nil, fmt.Errorf("xpath: unsupported function %s", name)
+	}
+
+	for !p.isOp(")") {
+		if len(f.args) > 0 {
+			if err := p.expectOp(","); err != nil {
+				return nil, err
+			}
+		}
+
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		f.args = append(f.args, arg)
+	}
+
+	p.next()
+
+	return f, nil
+}
+
+func (p *xpathParser) parsePath() (expr, error) {
+	path := &pathExpr{steps: make([]*step, 0)}
+	deep := false
+
+	if p.isOp("/", "//") {
+		path.absolute = true
+		deep = p.next().value == "//"
+
+		if !deep && !p.canStartStep() {
+			return path, nil
+		}
+	}
+
+	for {
+		s, err := p.parseStep()
+		if err != nil {
+			return nil, err
+		}
+
+		s.deep = deep
+		path.steps = append(path.steps, s)
+
+		if !p.isOp("/", "//") {
+			return path, nil
+		}
+
+		deep = p.next().value == "//"
+	}
+}
+
+func (p *xpathParser) canStartStep() bool {
+	tok := p.peek()
+	return tok.kind == xtName || (tok.kind == xtOp && (tok.value == "." || tok.value == ".." || tok.value == "@" || tok.value == "*"))
+}
+
+func (p *xpathParser) parseStep() (*step, error) {
+	tok := p.next()
+	s := &step{kind: stepChild}
+
+	switch {
+	case tok.kind == xtOp && tok.value == ".":
+		s.kind = stepSelf
+		return s, nil
+
+	case tok.kind == xtOp && tok.value == "..":
+		s.kind = stepParent
+		return s, nil
+
+	case tok.kind == xtOp && tok.value == "@":
+		name := p.next()
+		if name.kind != xtName && !(name.kind == xtOp && name.value == "*") {
+			return nil, fmt.Errorf("xpath: expected an attribute name. got %q", name.value)
+		}
+
+		s.kind = stepAttribute
+		s.name = localName(name.value)
+
+	case tok.kind == xtName && (tok.value == "text" || tok.value == "node") && p.isOp("("):
+		p.next()
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+
+		s.kind = stepText
+		if tok.value == "node" {
+			s.kind = stepNode
+		}
+
+	case tok.kind == xtName || (tok.kind == xtOp && tok.value == "*"):
+		s.name = localName(tok.value)
+
+	default:
+		return nil, fmt.Errorf("xpath: unexpected token %q", tok.value)
+	}
+
+	for p.isOp("[") {
+		p.next()
+
+		predicate, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = p.expectOp("]"); err != nil {
+			return nil, err
+		}
+
+		s.predicates = append(s.predicates, predicate)
+	}
+
+	return s, nil
+}
+
+// localName removes the namespace prefix from the given name.
+func localName(name string) string {
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+
+	return name
+}
+
+func tokenizeXPath(src string) ([]xpathToken, error) {
+	tokens := make([]xpathToken, 0)
+	i := 0
+
+	for i < len(src) {
+		c := src[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case strings.HasPrefix(src[i:], "//"), strings.HasPrefix(src[i:], ".."),
+			strings.HasPrefix(src[i:], "!="), strings.HasPrefix(src[i:], "<="), strings.HasPrefix(src[i:], ">="):
+			tokens = append(tokens, xpathToken{kind: xtOp, value: src[i : i+2]})
+			i += 2
+
+		case c == '.' && i+1 < len(src) && isXPathDigit(src[i+1]), isXPathDigit(c):
+			start := i
+			for i < len(src) && (isXPathDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+
+			tokens = append(tokens, xpathToken{kind: xtNumber, value: src[start:i]})
+
+		case strings.ContainsRune("/.@*[](),=<>|", rune(c)):
+			tokens = append(tokens, xpathToken{kind: xtOp, value: string(c)})
+			i++
+
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("xpath %s: unterminated string literal", src)
+			}
+
+			tokens = append(tokens, xpathToken{kind: xtLiteral, value: src[i+1 : i+1+end]})
+			i += end + 2
+
+		case isXPathNameStart(c):
+			start := i
+			for i < len(src) && (isXPathNameStart(src[i]) || isXPathDigit(src[i]) || src[i] == '-' || src[i] == '.' ||
+				(src[i] == ':' && i+1 < len(src) && src[i+1] != ':')) {
+				i++
+
+				// prefixed wildcard, like soap:*
+				if src[i-1] == ':' && i < len(src) && src[i] == '*' {
+					i++
+					break
+				}
+			}
+
+			tokens = append(tokens, xpathToken{kind: xtName, value: src[start:i]})
+
+		default:
+			return nil, fmt.Errorf("xpath %s: unexpected character %q", src, c)
+		}
+	}
+
+	return append(tokens, xpathToken{kind: xtEOF}), nil
+}
+
+func isXPathNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isXPathDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,101 @@
+package xmlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const _xml = `
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+	<soap:Body>
+		<order id="10" status="open">
+			<item sku="a1"><name>Book</name><price>10.5</price></item>
+			<item sku="b2"><name>Pen</name><price>2</price></item>
+			<item sku="c3"><name>Bag</name><price>30</price></item>
+		</order>
+	</soap:Body>
+</soap:Envelope>`
+
+func TestXPath(t *testing.T) {
+	doc, err := Parse([]byte(_xml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := func(expr string) any {
+		nodes := MustCompile(expr).Select(doc)
+		if len(nodes) == 0 {
+			return nil
+		}
+
+		return nodes[0].Text()
+	}
+
+	testCases := []struct {
+		expr     string
+		expected any
+	}{
+		{"/Envelope/Body/order/@id", "10"},
+		{"/soap:Envelope/soap:Body/order/@status", "open"},
+		{"//item[1]/name", "Book"},
+		{"//item[last()]/name", "Bag"},
+		{"//item[@sku='b2']/name", "Pen"},
+		{"//item[price > 20]/name", "Bag"},
+		{"//item[name='Pen' or name='Bag'][1]/@sku", "b2"},
+		{"//item[contains(name, 'oo')]/@sku", "a1"},
+		{"//item[not(@sku='a1') and price < 5]/name", "Pen"},
+		{"//order/item[2]/name/text()", "Pen"},
+		{"//name/..//price", "10.5"},
+		{"/*/*/*/@id", "10"},
+		{"//soap:*/order/@id", "10"},
+		{"//item[position() = 2]/name", "Pen"},
+		{".//order/./item/name", "Book"},
+		{"//missing", nil},
+		{"//item[@sku='x']", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			assert.Equal(t, tc.expected, text(tc.expr))
+		})
+	}
+}
+
+func TestXPath_Values(t *testing.T) {
+	doc, err := Parse([]byte(_xml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		expr     string
+		expected any
+	}{
+		{"count(//item)", float64(3)},
+		{"count(//item | //order)", float64(4)},
+		{"//item[1]/price = 10.5", true},
+		{"//item/name = 'Pen'", true},
+		{"//item/name != 'Book'", true},
+		{"string(//item[2]/@sku)", "b2"},
+		{"local-name(/*)", "Envelope"},
+		{"string-length(//item[1]/name)", float64(4)},
+		{"normalize-space('  a   b ')", "a b"},
+		{"starts-with(//order/@status, 'op')", true},
+		{"number(//item[3]/price) >= 30", true},
+		{"true() and not(false())", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			assert.Equal(t, tc.expected, MustCompile(tc.expr).Evaluate(doc))
+		})
+	}
+}
+
+func TestXPath_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "//item[", "//item[@sku='a1'", "unknown-fn(1)", "//item]", "'open", "//#"} {
+		_, err := Compile(expr)
+		assert.NotNil(t, err, expr)
+	}
+}
@@ -0,0 +1,168 @@
+package mocha
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/hooks"
+)
+
+// _defaultJournalSize is the default maximum number of requests retained by the request journal.
+const _defaultJournalSize = 1000
+
+type (
+	// RecordedRequest holds information about a request received by the mock server.
+	RecordedRequest struct {
+		// Request is a copy of the received http.Request, taken before any Reply ran.
+		// Its Body is always empty. Use Body to access the raw request body.
+		Request *http.Request
+
+		// Body is the raw request body.
+		Body []byte
+
+		// ParsedBody is the request body parsed by a RequestBodyParser.
+		ParsedBody any
+
+		// Matched indicates whether a Mock served the request.
+		Matched bool
+
+		// Mock is the Mock that served the request. It is nil when the request did not match.
+		Mock *Mock
+
+		// Forwarded indicates whether the request did not match any Mock and was forwarded to the record target.
+		Forwarded bool
+
+		// Mismatch holds the result of the matching attempt when no Mock matched the request.
+		Mismatch *hooks.Result
+
+		// Err is the error that happened while trying to serve the request, if any.
+		Err error
+
+		// Status is the HTTP status code sent to the client.
+		Status int
+
+		// StartedAt is the time the request was received.
+		StartedAt time.Time
+
+		// Elapsed is the time taken to serve the request.
+		Elapsed time.Duration
+	}
+
+	// requestJournal is a bounded, in-memory, history of received requests.
+	// When the limit is reached, the oldest entries are discarded.
+	requestJournal struct {
+		entries []*RecordedRequest
+		limit   int
+		next    int
+		full    bool
+		mu      sync.RWMutex
+	}
+)
+
+func newRequestJournal(limit int) *requestJournal {
+	if limit <= 0 {
+		limit = _defaultJournalSize
+	}
+
+	return &requestJournal{entries: make([]*RecordedRequest, limit), limit: limit}
+}
+
+// Record adds a new entry to the journal.
+func (j *requestJournal) Record(entry *RecordedRequest) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries[j.next] = entry
+	j.next = (j.next + 1) % j.limit
+
+	if j.next == 0 {
+		j.full = true
+	}
+}
+
+// FetchAll returns all journal entries, from the oldest to the newest.
+func (j *requestJournal) FetchAll() []*RecordedRequest {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if !j.full {
+		list := make([]*RecordedRequest, j.next)
+		copy(list, j.entries[:j.next])
+
+		return list
+	}
+
+	list := make([]*RecordedRequest, 0, j.limit)
+	list = append(list, j.entries[j.next:]...)
+	list = append(list, j.entries[:j.next]...)
+
+	return list
+}
+
+// Flush removes all journal entries.
+func (j *requestJournal) Flush() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries = make([]*RecordedRequest, j.limit)
+	j.next = 0
+	j.full = false
+}
+
+// RequestInfo returns an expect.RequestInfo for the recorded request, with a fresh copy of the request body,
+// so it can be evaluated by matchers.
+func (rr *RecordedRequest) RequestInfo() *expect.RequestInfo {
+	r := rr.Request.WithContext(context.Background())
+	r.Body = io.NopCloser(bytes.NewReader(rr.Body))
+
+	return &expect.RequestInfo{Request: r, ParsedBody: rr.ParsedBody}
+}
+
+// Requests returns the requests received by the mock server, from the oldest to the newest.
+// Only requests matching all the given matchers are returned. Matchers receive the recorded *http.Request.
+// The number of retained requests is limited by Config.JournalSize.
+//
+// Usage:
+//
+//	m.Requests(expect.Func(func(v any, a expect.Args) (bool, error) {
+//		return v.(*http.Request).Method == http.MethodPost, nil
+//	}))
+func (m *Mocha) Requests(filters ...expect.Matcher) []*RecordedRequest {
+	entries := m.journal.FetchAll()
+	if len(filters) == 0 {
+		return entries
+	}
+
+	result := make([]*RecordedRequest, 0)
+
+entries:
+	for _, entry := range entries {
+		info := entry.RequestInfo()
+		args := expect.Args{RequestInfo: info, Params: m.params}
+
+		for _, filter := range filters {
+			matched, err := filter.Matches(info.Request, args)
+			if err != nil {
+				m.t.Logf("\nerror filtering recorded requests with matcher %s. error=%v", filter.Name, err)
+			}
+
+			if err != nil || !matched {
+				continue entries
+			}
+		}
+
+		result = append(result, entry)
+	}
+
+	return result
+}
+
+// ResetRequests removes all recorded requests.
+func (m *Mocha) ResetRequests() {
+	m.journal.Flush()
+}
@@ -0,0 +1,106 @@
+package mocha
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/testutil"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func TestRequestJournal(t *testing.T) {
+	j := newRequestJournal(3)
+
+	for i := 1; i <= 2; i++ {
+		j.Record(&RecordedRequest{Status: i})
+	}
+
+	entries := j.FetchAll()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, 1, entries[0].Status)
+	assert.Equal(t, 2, entries[1].Status)
+
+	for i := 3; i <= 5; i++ {
+		j.Record(&RecordedRequest{Status: i})
+	}
+
+	entries = j.FetchAll()
+	assert.Len(t, entries, 3)
+	assert.Equal(t, 3, entries[0].Status)
+	assert.Equal(t, 4, entries[1].Status)
+	assert.Equal(t, 5, entries[2].Status)
+
+	j.Flush()
+
+	assert.Len(t, j.FetchAll(), 0)
+}
+
+func TestMocha_Requests(t *testing.T) {
+	m := New(t)
+	m.Start()
+
+	scoped := m.AddMocks(Post(expect.URLPath("/test")).
+		Header("x-test", expect.ToEqual("ok")).
+		Reply(reply.Created().BodyString("hello")))
+
+	res, err := testutil.PostJSON(m.URL()+"/test", map[string]any{"name": "dev"}).Header("x-test", "ok").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	res, err = testutil.Get(m.URL() + "/other").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	requests := m.Requests()
+	assert.Len(t, requests, 2)
+
+	matched := requests[0]
+	assert.True(t, matched.Matched)
+	assert.Equal(t, scoped.ListAll()[0], matched.Mock)
+	assert.Equal(t, http.MethodPost, matched.Request.Method)
+	assert.Equal(t, "/test", matched.Request.URL.Path)
+	assert.Equal(t, "ok", matched.Request.Header.Get("x-test"))
+	assert.Equal(t, `{"name":"dev"}`, string(matched.Body))
+	assert.Equal(t, map[string]any{"name": "dev"}, matched.ParsedBody)
+	assert.Equal(t, http.StatusCreated, matched.Status)
+	assert.Nil(t, matched.Mismatch)
+	assert.False(t, matched.StartedAt.IsZero())
+
+	notMatched := requests[1]
+	assert.False(t, notMatched.Matched)
+	assert.Nil(t, notMatched.Mock)
+	assert.NotNil(t, notMatched.Mismatch)
+	assert.True(t, notMatched.Mismatch.HasClosestMatch)
+	assert.Equal(t, http.StatusTeapot, notMatched.Status)
+
+	t.Run("should filter requests", func(t *testing.T) {
+		filtered := m.Requests(expect.Func(func(v any, a expect.Args) (bool, error) {
+			return v.(*http.Request).Method == http.MethodPost, nil
+		}))
+
+		assert.Len(t, filtered, 1)
+		assert.Equal(t, matched, filtered[0])
+
+		filtered = m.Requests(expect.Func(func(v any, a expect.Args) (bool, error) {
+			body, ok := a.RequestInfo.ParsedBody.(map[string]any)
+			return ok && strings.Contains(body["name"].(string), "qa"), nil
+		}))
+
+		assert.Len(t, filtered, 0)
+	})
+
+	t.Run("should reset recorded requests", func(t *testing.T) {
+		m.ResetRequests()
+		assert.Len(t, m.Requests(), 0)
+	})
+}
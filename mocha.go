@@ -15,15 +15,20 @@ import (
 type (
 	// Mocha is the base for the mock server.
 	Mocha struct {
-		server  Server
-		storage storage
-		context context.Context
-		cancel  context.CancelFunc
-		params  params.P
-		events  *hooks.Emitter
-		scopes  []*Scoped
-		mu      *sync.Mutex
-		t       T
+		server    Server
+		storage   storage
+		scenarios scenarioStore
+		context   context.Context
+		cancel    context.CancelFunc
+		closeConn context.CancelFunc
+		params    params.P
+		journal   *requestJournal
+		strict    *strictLog
+		handler   *mockHandler
+		events    *hooks.Emitter
+		scopes    []*Scoped
+		mu        *sync.Mutex
+		t         T
 	}
 
 	// Cleanable allows marking mocha instance to be closed on test cleanup.
@@ -47,10 +52,11 @@ func New(t T, config ...Config) *Mocha {
 	ctx, cancel := context.WithCancel(parent)
 
 	mockStorage := newStorage()
+	scenarios := newScenarioStore()
 
 	parsers := make([]RequestBodyParser, 0)
 	parsers = append(parsers, cfg.BodyParsers...)
-	parsers = append(parsers, &jsonBodyParser{}, &plainTextParser{}, &formURLEncodedParser{}, &bytesParser{})
+	parsers = append(parsers, &jsonBodyParser{}, &xmlBodyParser{}, &plainTextParser{}, &formURLEncodedParser{}, &multipartFormParser{}, &bytesParser{})
 
 	middlewares := make([]func(handler http.Handler) http.Handler, 0)
 	middlewares = append(middlewares, recover.Recover)
@@ -67,9 +73,49 @@ func New(t T, config ...Config) *Mocha {
 
 	middlewares = append(middlewares, cfg.Middlewares...)
 	p := params.New()
+
+	m := &Mocha{
+		storage:   mockStorage,
+		scenarios: scenarios,
+		context:   ctx,
+		cancel:    cancel,
+		params:    p,
+		journal:   newRequestJournal(cfg.JournalSize),
+		scopes:    make([]*Scoped, 0),
+		events:    evt,
+		mu:        &sync.Mutex{},
+		t:         t}
+
+	if cfg.Strict {
+		m.strict = newStrictLog()
+	}
+
+	var rec *recorder
+	if cfg.recordEnabled {
+		var err error
+		rec, err = newRecorder(cfg.Record)
+		if err != nil {
+			t.Errorf("failed to configure recording. reason=%v", err)
+			t.FailNow()
+		}
+	}
+
+	connCtx, closeConn := context.WithCancel(ctx)
+	m.closeConn = closeConn
+
+	m.handler = newHandler(
+		connCtx,
+		mockStorage, scenarios, parsers, p, m.journal, m.strict, rec, cfg.NotMatchedReply, cfg.ErrorReply, evt, t)
+
+	var root http.Handler = m.handler
+
+	if cfg.Admin {
+		root = newAdminHandler(m, root)
+	}
+
 	handler := middleware.
 		Compose(middlewares...).
-		Root(newHandler(mockStorage, newScenarioStore(), parsers, p, evt, t))
+		Root(root)
 
 	server := cfg.Server
 
@@ -83,16 +129,7 @@ func New(t T, config ...Config) *Mocha {
 		t.FailNow()
 	}
 
-	m := &Mocha{
-		server:  server,
-		storage: mockStorage,
-		context: ctx,
-		cancel:  cancel,
-		params:  p,
-		scopes:  make([]*Scoped, 0),
-		events:  evt,
-		mu:      &sync.Mutex{},
-		t:       t}
+	m.server = server
 
 	go func() {
 		<-ctx.Done()
@@ -179,8 +216,9 @@ func (m *Mocha) Subscribe(evt hooks.Events) {
 	m.events.Subscribe(evt)
 }
 
-// Close closes the mock server.
+// Close closes the mock server, along with upgraded connections, like WebSocket ones.
 func (m *Mocha) Close() error {
+	m.closeConn()
 	return m.server.Close()
 }
 
@@ -210,6 +248,7 @@ func (m *Mocha) Enable() {
 }
 
 // CloseOnCleanup adds mocha server Close to the Cleanup.
+// When strict mode is enabled, requests not served by a mock are reported as test errors after closing the server.
 func (m *Mocha) CloseOnCleanup(t Cleanable) *Mocha {
 	closeSrv := func() {
 		e := m.Close()
@@ -221,6 +260,7 @@ func (m *Mocha) CloseOnCleanup(t Cleanable) *Mocha {
 	t.Cleanup(func() {
 		defer m.cancel()
 		closeSrv()
+		m.reportStrict()
 	})
 
 	return m
@@ -267,7 +307,7 @@ func (m *Mocha) AssertHits(t T, expected int) bool {
 
 	hits := m.Hits()
 
-	if hits < expected {
+	if hits != expected {
 		t.Errorf("\nexpected %d request hits. got %d", expected, hits)
 		return false
 	}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -213,6 +214,8 @@ func TestMocha_Assertions(t *testing.T) {
 	assert.True(t, m.AssertCalled(fakeT))
 	assert.False(t, m.AssertNotCalled(fakeT))
 	assert.True(t, m.AssertHits(fakeT, 1))
+	assert.False(t, m.AssertHits(fakeT, 0))
+	assert.False(t, m.AssertHits(fakeT, 2))
 	assert.Equal(t, 1, m.Hits())
 }
 
@@ -402,3 +405,131 @@ func TestMocha_Silently(t *testing.T) {
 	assert.Equal(t, 201, res.StatusCode)
 	assert.Equal(t, string(body), "hello world")
 }
+
+func TestMocha_NotMatchedReply(t *testing.T) {
+	m := New(t, Configure().
+		NotMatchedReply(reply.NotFound().BodyJSON(map[string]any{"error": "not found"})).
+		Build())
+	m.Start()
+	defer m.Close()
+
+	scoped := m.AddMocks(Get(expect.URLPath("/test")).Reply(reply.OK()))
+
+	res, err := testutil.Get(m.URL() + "/other").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+
+	assert.False(t, scoped.Called())
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	assert.JSONEq(t, `{"error":"not found"}`, string(body))
+
+	requests := m.Requests()
+	assert.Len(t, requests, 1)
+	assert.False(t, requests[0].Matched)
+	assert.Equal(t, http.StatusNotFound, requests[0].Status)
+	assert.NotNil(t, requests[0].Mismatch)
+	assert.True(t, requests[0].Mismatch.HasClosestMatch)
+
+	t.Run("should forward not matched requests", func(t *testing.T) {
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("from " + r.URL.Path))
+		}))
+		defer target.Close()
+
+		m := New(t, Configure().NotMatchedReply(reply.From(target.URL)).Build())
+		m.Start()
+		defer m.Close()
+
+		m.AddMocks(Get(expect.URLPath("/mocked")).Reply(reply.OK().BodyString("mocked")))
+
+		res, err := testutil.Get(m.URL() + "/mocked").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		assert.Equal(t, "mocked", string(body))
+
+		res, err = testutil.Get(m.URL() + "/real").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ = io.ReadAll(res.Body)
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "from /real", string(body))
+	})
+}
+
+func TestMocha_ErrorReply(t *testing.T) {
+	m := New(t, Configure().
+		ErrorReply(reply.InternalServerError().BodyString("failed")).
+		Build())
+	m.Start()
+	defer m.Close()
+
+	scoped := m.AddMocks(Get(expect.URLPath("/test")).
+		ReplyFunction(func(r *http.Request, m reply.M, p params.P) (*reply.Response, error) {
+			return nil, fmt.Errorf("failed to build a response")
+		}))
+
+	res, err := testutil.Get(m.URL() + "/test").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+
+	assert.False(t, scoped.Called())
+	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	assert.Equal(t, "failed", string(body))
+
+	requests := m.Requests()
+	assert.Len(t, requests, 1)
+	assert.Error(t, requests[0].Err)
+	assert.Equal(t, http.StatusInternalServerError, requests[0].Status)
+
+	t.Run("should not use the error reply for not matched requests", func(t *testing.T) {
+		res, err := testutil.Get(m.URL() + "/other").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusTeapot, res.StatusCode)
+	})
+
+	t.Run("should respond the default error when the error reply fails", func(t *testing.T) {
+		m := New(t, Configure().
+			ErrorReply(reply.Function(func(r *http.Request, m reply.M, p params.P) (*reply.Response, error) {
+				return nil, fmt.Errorf("fallback failed")
+			})).
+			Build())
+		m.Start()
+		defer m.Close()
+
+		m.AddMocks(Get(expect.URLPath("/test")).
+			ReplyFunction(func(r *http.Request, m reply.M, p params.P) (*reply.Response, error) {
+				return nil, fmt.Errorf("failed to build a response")
+			}))
+
+		res, err := testutil.Get(m.URL() + "/test").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusTeapot, res.StatusCode)
+	})
+}
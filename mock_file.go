@@ -0,0 +1,602 @@
+package mocha
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+type (
+	// mockFileDefinition is the declarative representation of a Mock, loaded from JSON or YAML files.
+	mockFileDefinition struct {
+		Name     string                  `json:"name,omitempty"`
+		Priority int                     `json:"priority,omitempty"`
+		Enabled  *bool                   `json:"enabled,omitempty"`
+		Repeat   int                     `json:"repeat,omitempty"`
+		Scenario *scenarioFileDefinition `json:"scenario,omitempty"`
+		Request  requestFileDefinition   `json:"request"`
+		Response responseFileDefinition  `json:"response"`
+	}
+
+	// scenarioFileDefinition holds the scenario fields of a mockFileDefinition.
+	scenarioFileDefinition struct {
+		Name          string `json:"name"`
+		RequiredState string `json:"required_state,omitempty"`
+		NewState      string `json:"new_state,omitempty"`
+	}
+
+	// requestFileDefinition holds the request expectations of a mockFileDefinition.
+	// Every matcher field accepts the format described by matcherFromDefinition.
+	requestFileDefinition struct {
+		Method  string         `json:"method,omitempty"`
+		URL     any            `json:"url,omitempty"`
+		Query   map[string]any `json:"query,omitempty"`
+		Headers map[string]any `json:"headers,omitempty"`
+		Form    map[string]any `json:"form,omitempty"`
+		Body    any            `json:"body,omitempty"`
+	}
+
+	// responseFileDefinition holds the response stub of a mockFileDefinition.
+	responseFileDefinition struct {
+		Status     int                     `json:"status,omitempty"`
+		Headers    map[string]headerValues `json:"headers,omitempty"`
+		Body       string                  `json:"body,omitempty"`
+		BodyJSON   any                     `json:"body_json,omitempty"`
+		BodyBase64 string                  `json:"body_base64,omitempty"`
+		BodyFile   string                  `json:"body_file,omitempty"`
+		Template   bool                    `json:"template,omitempty"`
+		Delay      string                  `json:"delay,omitempty"`
+	}
+
+	// headerValues accepts a single string or a list of strings.
+	headerValues []string
+)
+
+// UnmarshalJSON decodes a single string or a list of strings.
+func (h *headerValues) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*h = headerValues{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return fmt.Errorf("header value must be a string or a list of strings. got %s", string(b))
+	}
+
+	*h = list
+
+	return nil
+}
+
+// MarshalJSON encodes a single value as a plain string.
+func (h headerValues) MarshalJSON() ([]byte, error) {
+	if len(h) == 1 {
+		return json.Marshal(h[0])
+	}
+
+	return json.Marshal([]string(h))
+}
+
+// LoadMocksFromDir loads all mock definition files (.json, .yaml and .yml) from the given directory and its
+// subdirectories, adding them to the mock server.
+// It returns a Scoped instance containing every loaded mock.
+//
+// Usage:
+//
+//	scoped, err := m.LoadMocksFromDir("testdata/mocks")
+func (m *Mocha) LoadMocksFromDir(dir string) (*Scoped, error) {
+	files := make([]string, 0)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && isMockFile(path) {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return m.LoadMocksFromFile(files...)
+}
+
+// LoadMocksFromFile loads mock definitions from the given JSON or YAML files and adds them to the mock server.
+// A single file can contain one mock definition or a list of them.
+// It returns a Scoped instance containing every loaded mock.
+func (m *Mocha) LoadMocksFromFile(filenames ...string) (*Scoped, error) {
+	builders := make([]*MockBuilder, 0, len(filenames))
+
+	for _, filename := range filenames {
+		b, err := mockBuildersFromFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		builders = append(builders, b...)
+	}
+
+	return m.AddMocks(builders...), nil
+}
+
+func isMockFile(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
+
+// mockBuildersFromFile reads and decodes the given mock definition file, returning a MockBuilder for each definition.
+func mockBuildersFromFile(filename string) ([]*MockBuilder, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions, err := decodeMockFile(filename, content)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding mock file %s. reason=%v", filename, err)
+	}
+
+	builders := make([]*MockBuilder, len(definitions))
+
+	for i, def := range definitions {
+		if def.Name == "" {
+			def.Name = filepath.Base(filename)
+		}
+
+		builder, err := mockBuilderFromDefinition(def, filepath.Dir(filename))
+		if err != nil {
+			return nil, fmt.Errorf("error building mock from file %s. reason=%v", filename, err)
+		}
+
+		builders[i] = builder
+	}
+
+	return builders, nil
+}
+
+// decodeMockFile decodes JSON or YAML files content into mock definitions, based on the file extension.
+func decodeMockFile(filename string, content []byte) ([]*mockFileDefinition, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return decodeMockDefinitions(content, ext == ".yaml" || ext == ".yml")
+}
+
+// decodeMockDefinitions decodes JSON or YAML content into mock definitions.
+// YAML content is converted to JSON first, so both formats share the same decoding rules.
+func decodeMockDefinitions(content []byte, isYAML bool) ([]*mockFileDefinition, error) {
+	if isYAML {
+		var data any
+		err := yaml.Unmarshal(content, &data)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err = json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	content = bytes.TrimSpace(content)
+
+	if bytes.HasPrefix(content, []byte("[")) {
+		definitions := make([]*mockFileDefinition, 0)
+		err := json.Unmarshal(content, &definitions)
+
+		return definitions, err
+	}
+
+	def := &mockFileDefinition{}
+	err := json.Unmarshal(content, def)
+
+	return []*mockFileDefinition{def}, err
+}
+
+// mockBuilderFromDefinition creates a MockBuilder from a mockFileDefinition.
+// Relative file references, like response body files, are resolved using the given base directory.
+func mockBuilderFromDefinition(def *mockFileDefinition, baseDir string) (*MockBuilder, error) {
+	b := Request().Name(def.Name).Priority(def.Priority).Repeat(def.Repeat)
+
+	if def.Enabled != nil && !*def.Enabled {
+		b.mock.Enabled = false
+	}
+
+	if def.Scenario != nil {
+		b.ScenarioIs(def.Scenario.Name).
+			ScenarioStateIs(def.Scenario.RequiredState).
+			ScenarioStateWillBe(def.Scenario.NewState)
+
+		if def.Scenario.RequiredState == "" {
+			b.ScenarioStateIs(_scenarioStateStarted)
+		}
+	}
+
+	req := def.Request
+
+	if req.Method != "" {
+		b.Method(req.Method)
+	}
+
+	if req.URL != nil {
+		if path, ok := req.URL.(string); ok {
+			b.URL(expect.URLPath(path))
+		} else {
+			m, err := matcherFromDefinition(req.URL)
+			if err != nil {
+				return nil, fmt.Errorf("url: %v", err)
+			}
+
+			b.URL(urlPathMatcher(m))
+		}
+	}
+
+	for _, key := range sortedKeys(req.Headers) {
+		m, err := stringMatcherFromDefinition(req.Headers[key])
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %v", key, err)
+		}
+
+		b.Header(key, m)
+	}
+
+	for _, key := range sortedKeys(req.Query) {
+		m, err := stringMatcherFromDefinition(req.Query[key])
+		if err != nil {
+			return nil, fmt.Errorf("query %s: %v", key, err)
+		}
+
+		b.Query(key, m)
+	}
+
+	for _, key := range sortedKeys(req.Form) {
+		m, err := stringMatcherFromDefinition(req.Form[key])
+		if err != nil {
+			return nil, fmt.Errorf("form field %s: %v", key, err)
+		}
+
+		b.FormField(key, m)
+	}
+
+	if req.Body != nil {
+		list, ok := req.Body.([]any)
+		if !ok {
+			list = []any{req.Body}
+		}
+
+		for i, entry := range list {
+			m, err := matcherFromDefinition(entry)
+			if err != nil {
+				return nil, fmt.Errorf("body matcher %d: %v", i, err)
+			}
+
+			b.Body(m)
+		}
+	}
+
+	rep, err := replyFromDefinition(&def.Response, baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	b.Reply(rep)
+
+	return b, nil
+}
+
+func replyFromDefinition(def *responseFileDefinition, baseDir string) (*reply.StdReply, error) {
+	status := def.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	rep := reply.Status(status)
+
+	for _, key := range sortedKeys(def.Headers) {
+		for _, value := range def.Headers[key] {
+			rep.Header(key, value)
+		}
+	}
+
+	body := []byte(def.Body)
+
+	switch {
+	case def.BodyFile != "":
+		filename := def.BodyFile
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(baseDir, filename)
+		}
+
+		if !def.Template {
+			if _, err := os.Stat(filename); err != nil {
+				return nil, err
+			}
+
+			rep.BodyFile(filename)
+
+			break
+		}
+
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		body = content
+
+	case def.BodyJSON != nil:
+		content, err := json.Marshal(def.BodyJSON)
+		if err != nil {
+			return nil, err
+		}
+
+		body = content
+
+	case def.BodyBase64 != "":
+		content, err := base64.StdEncoding.DecodeString(def.BodyBase64)
+		if err != nil {
+			return nil, fmt.Errorf("response body_base64: %v", err)
+		}
+
+		body = content
+	}
+
+	if def.Template {
+		rep.BodyTemplate(string(body))
+	} else if len(body) > 0 {
+		rep.Body(body)
+	}
+
+	if def.Delay != "" {
+		delay, err := time.ParseDuration(def.Delay)
+		if err != nil {
+			return nil, fmt.Errorf("response delay: %v", err)
+		}
+
+		rep.Delay(delay)
+	}
+
+	return rep, nil
+}
+
+// matcherFromDefinition builds an expect.Matcher from its declarative form.
+// Scalar values are compared using expect.ToEqual.
+// Objects must use matcher names as keys. Objects with multiple keys are combined using expect.AllOf.
+// Example:
+//
+//	{ "contains": "dev" }
+//	{ "not": { "empty": true } }
+//	{ "json_path": { "address.city": "Santiago" } }
+func matcherFromDefinition(def any) (expect.Matcher, error) {
+	obj, ok := def.(map[string]any)
+	if !ok {
+		if _, isList := def.([]any); isList {
+			return expect.Matcher{}, fmt.Errorf("list values must be wrapped by a matcher. e.g.: { \"equal\": [...] }")
+		}
+
+		return expect.ToEqual(def), nil
+	}
+
+	keys := sortedKeys(obj)
+	if len(keys) == 0 {
+		return expect.Matcher{}, fmt.Errorf("matcher definition is empty")
+	}
+
+	matchers := make([]expect.Matcher, len(keys))
+
+	for i, key := range keys {
+		m, err := namedMatcherFromDefinition(key, obj[key])
+		if err != nil {
+			return expect.Matcher{}, err
+		}
+
+		matchers[i] = m
+	}
+
+	if len(matchers) == 1 {
+		return matchers[0], nil
+	}
+
+	return expect.AllOf(matchers...), nil
+}
+
+// stringMatcherFromDefinition works like matcherFromDefinition for targets that always have string values,
+// like headers, query parameters and form fields. Number and boolean scalars are compared to their string forms.
+func stringMatcherFromDefinition(def any) (expect.Matcher, error) {
+	switch e := def.(type) {
+	case bool:
+		return expect.ToEqual(strconv.FormatBool(e)), nil
+	case int:
+		return expect.ToEqual(strconv.Itoa(e)), nil
+	case float64:
+		return expect.ToEqual(strconv.FormatFloat(e, 'f', -1, 64)), nil
+	}
+
+	return matcherFromDefinition(def)
+}
+
+func namedMatcherFromDefinition(name string, arg any) (expect.Matcher, error) {
+	switch name {
+	case "equal":
+		return expect.ToEqual(arg), nil
+	case "equal_fold":
+		s, err := definitionString(name, arg)
+		return expect.ToEqualFold(s), err
+	case "equal_json":
+		return expect.ToEqualJSON(arg), nil
+	case "contains":
+		return expect.ToContain(arg), nil
+	case "prefix":
+		s, err := definitionString(name, arg)
+		return expect.ToHavePrefix(s), err
+	case "suffix":
+		s, err := definitionString(name, arg)
+		return expect.ToHaveSuffix(s), err
+	case "regex":
+		s, err := definitionString(name, arg)
+		return expect.ToMatchExpr(s), err
+	case "has_key":
+		s, err := definitionString(name, arg)
+		return expect.ToHaveKey(s), err
+	case "len":
+		n, ok := arg.(float64)
+		if !ok {
+			return expect.Matcher{}, fmt.Errorf("matcher len expects a number. got %v", arg)
+		}
+
+		return expect.ToHaveLen(int(n)), nil
+	case "empty":
+		return booleanMatcher(name, arg, expect.ToBeEmpty())
+	case "present":
+		return booleanMatcher(name, arg, expect.ToBePresent())
+	case "not", "lowercase", "uppercase", "trim":
+		m, err := matcherFromDefinition(arg)
+		if err != nil {
+			return expect.Matcher{}, err
+		}
+
+		switch name {
+		case "not":
+			return expect.Not(m), nil
+		case "lowercase":
+			return expect.LowerCase(m), nil
+		case "uppercase":
+			return expect.UpperCase(m), nil
+		default:
+			return expect.Trim(m), nil
+		}
+	case "all_of", "any_of":
+		list, ok := arg.([]any)
+		if !ok {
+			return expect.Matcher{}, fmt.Errorf("matcher %s expects a list of matchers", name)
+		}
+
+		matchers := make([]expect.Matcher, len(list))
+		for i, entry := range list {
+			m, err := matcherFromDefinition(entry)
+			if err != nil {
+				return expect.Matcher{}, err
+			}
+
+			matchers[i] = m
+		}
+
+		if name == "all_of" {
+			return expect.AllOf(matchers...), nil
+		}
+
+		return expect.AnyOf(matchers...), nil
+	case "json_path":
+		paths, ok := arg.(map[string]any)
+		if !ok || len(paths) == 0 {
+			return expect.Matcher{}, fmt.Errorf("matcher json_path expects an object with paths as keys")
+		}
+
+		keys := sortedKeys(paths)
+		matchers := make([]expect.Matcher, len(keys))
+		for i, path := range keys {
+			m, err := matcherFromDefinition(paths[path])
+			if err != nil {
+				return expect.Matcher{}, err
+			}
+
+			matchers[i] = expect.JSONPath(path, m)
+		}
+
+		if len(matchers) == 1 {
+			return matchers[0], nil
+		}
+
+		return expect.AllOf(matchers...), nil
+	case "json_schema":
+		switch arg.(type) {
+		case map[string]any, bool:
+		default:
+			return expect.Matcher{}, fmt.Errorf("matcher json_schema expects a schema object")
+		}
+
+		schema, err := json.Marshal(arg)
+		if err != nil {
+			return expect.Matcher{}, err
+		}
+
+		return expect.ToMatchJSONSchema(string(schema)), nil
+	}
+
+	return expect.Matcher{}, fmt.Errorf("unknown matcher %s", name)
+}
+
+func booleanMatcher(name string, arg any, m expect.Matcher) (expect.Matcher, error) {
+	v, ok := arg.(bool)
+	if !ok {
+		return expect.Matcher{}, fmt.Errorf("matcher %s expects a boolean. got %v", name, arg)
+	}
+
+	if v {
+		return m, nil
+	}
+
+	return expect.Not(m), nil
+}
+
+func definitionString(name string, arg any) (string, error) {
+	s, ok := arg.(string)
+	if !ok {
+		return "", fmt.Errorf("matcher %s expects a string. got %v", name, arg)
+	}
+
+	return s, nil
+}
+
+// urlPathMatcher applies the given matcher to the request URL path.
+func urlPathMatcher(matcher expect.Matcher) expect.Matcher {
+	m := expect.Matcher{}
+	m.Name = "URLPath" + matcher.Name
+	m.Literal = matcher.Literal
+	m.DescribeMismatch = func(p string, v any) string {
+		if u, ok := v.(*url.URL); ok && matcher.DescribeMismatch != nil {
+			return matcher.DescribeMismatch(p, u.Path)
+		}
+
+		return fmt.Sprintf("matcher %s applied on url path did not match", matcher.Name)
+	}
+	m.Matches = func(v any, args expect.Args) (bool, error) {
+		return matcher.Matches(v.(*url.URL).Path, args)
+	}
+
+	return m
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
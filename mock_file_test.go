@@ -0,0 +1,211 @@
+package mocha
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/testutil"
+)
+
+func TestMocha_LoadMocksFromDir(t *testing.T) {
+	m := New(t)
+	m.Start()
+
+	scoped, err := m.LoadMocksFromDir("_testdata/mocks")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, scoped.ListAll(), 4)
+
+	t.Run("json definition", func(t *testing.T) {
+		// the same body must be served on every request.
+		for i := 0; i < 2; i++ {
+			res, err := testutil.Get(m.URL()+"/users/1?fields=name").Header("accept", "application/json").Do()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body := make(map[string]any)
+			err = json.NewDecoder(res.Body).Decode(&body)
+			res.Body.Close()
+
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "application/json", res.Header.Get("content-type"))
+			assert.Equal(t, []string{"a", "b"}, res.Header.Values("x-tags"))
+			assert.Equal(t, "dev", body["name"])
+		}
+	})
+
+	t.Run("yaml definition", func(t *testing.T) {
+		res, err := testutil.PostJSON(m.URL()+"/users", map[string]any{"name": "dev", "age": 42, "tags": []string{"go"}}).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, "created", string(body))
+	})
+
+	t.Run("multiple definitions in a single file with scenarios", func(t *testing.T) {
+		res, err := testutil.Get(m.URL() + "/scenario/1").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/plain; charset=utf-8", res.Header.Get("content-type"))
+		assert.Equal(t, "hello world", string(body))
+
+		res, err = testutil.Get(m.URL() + "/scenario/2").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	})
+
+	scoped.AssertCalled(t)
+}
+
+func TestMocha_LoadMocksFromFile_ScalarStringTargets(t *testing.T) {
+	definitions := map[string]string{
+		"scalars.json": `{
+			"request": {
+				"method": "POST",
+				"url": "/q",
+				"headers": {"x-version": 1},
+				"query": {"page": 2, "ratio": 0.5},
+				"form": {"active": true}
+			},
+			"response": {"status": 200}
+		}`,
+		"scalars.yaml": `
+request:
+  method: POST
+  url: /q
+  headers:
+    x-version: 1
+  query:
+    page: 2
+    ratio: 0.5
+  form:
+    active: true
+response:
+  status: 200
+`,
+	}
+
+	for name, definition := range definitions {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(filename, []byte(definition), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			m := New(t, Configure().LogVerbosity(LogSilently).Build())
+			m.Start()
+			defer m.Close()
+
+			_, err := m.LoadMocksFromFile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, m.URL()+"/q?page=2&ratio=0.5", strings.NewReader("active=true"))
+			req.Header.Set("content-type", "application/x-www-form-urlencoded")
+			req.Header.Set("x-version", "1")
+
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res.Body.Close()
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+		})
+	}
+}
+
+func TestMocha_LoadMocksFromFile_Errors(t *testing.T) {
+	m := New(t)
+
+	_, err := m.LoadMocksFromFile("_testdata/mocks/not_found.json")
+	assert.NotNil(t, err)
+}
+
+func TestMatcherFromDefinition(t *testing.T) {
+	testCases := []struct {
+		name       string
+		definition string
+		value      any
+		expected   bool
+		err        bool
+	}{
+		{"scalar", `"dev"`, "dev", true, false},
+		{"equal", `{"equal": 10}`, float64(10), true, false},
+		{"equal fold", `{"equal_fold": "DEV"}`, "dev", true, false},
+		{"contains", `{"contains": "ev"}`, "dev", true, false},
+		{"prefix and suffix", `{"prefix": "d", "suffix": "v"}`, "dev", true, false},
+		{"regex", `{"regex": "^d.v$"}`, "dev", true, false},
+		{"len", `{"len": 3}`, "dev", true, false},
+		{"not empty", `{"empty": false}`, "dev", true, false},
+		{"present", `{"present": true}`, "", false, false},
+		{"not", `{"not": "qa"}`, "dev", true, false},
+		{"lowercase", `{"lowercase": "dev"}`, "DEV", true, false},
+		{"trim", `{"trim": "dev"}`, "  dev ", true, false},
+		{"any of", `{"any_of": ["qa", "dev"]}`, "dev", true, false},
+		{"all of", `{"all_of": ["qa", "dev"]}`, "dev", false, false},
+		{"json path", `{"json_path": {"a.b": 1}}`, map[string]any{"a": map[string]any{"b": float64(1)}}, true, false},
+		{"has key", `{"has_key": "a"}`, map[string]any{"a": 1}, true, false},
+		{"json schema", `{"json_schema": {"required": ["a"]}}`, map[string]any{"b": 1}, false, false},
+		{"invalid json schema", `{"json_schema": "schema.json"}`, "", false, true},
+		{"unknown", `{"unknown": 1}`, "", false, true},
+		{"empty object", `{}`, "", false, true},
+		{"list", `[1, 2]`, "", false, true},
+		{"invalid argument", `{"prefix": 1}`, "", false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var def any
+			err := json.NewDecoder(strings.NewReader(tc.definition)).Decode(&def)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			m, err := matcherFromDefinition(def)
+			if tc.err {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+
+			res, err := m.Matches(tc.value, expect.Args{})
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
@@ -13,10 +13,43 @@ type findResult struct {
 }
 
 // findMockForRequest tries to find a mock to the incoming HTTP request.
-// It runs all matchers of all eligible mocks on request until it finds one that matches every one of then.
+// Candidates are narrowed down by the storage index, using the request method and URL path, and then
+// all their matchers run until one matches every one of them.
+// When no mock matches, all eligible mocks are evaluated to describe the mismatches and find the closest match.
 // It returns a findResult with the find result, along with a possible closest match.
 func findMockForRequest(storage storage, params expect.Args) (*findResult, error) {
-	var mocks = storage.FetchEligible()
+	var r = params.RequestInfo.Request
+	var matched *Mock
+	var err error
+
+	storage.ForEachEligible(r.Method, r.URL.Path, func(m *Mock) bool {
+		var result matchResult
+		result, err = m.matches(params, m.Expectations)
+		if err != nil {
+			return false
+		}
+
+		if result.IsMatch {
+			matched = m
+			return false
+		}
+
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if matched != nil {
+		return &findResult{Matches: true, Matched: matched}, nil
+	}
+
+	return scanMocks(storage.FetchEligible(), params)
+}
+
+// scanMocks runs all matchers of the given mocks on request until it finds one that matches every one of then.
+func scanMocks(mocks []*Mock, params expect.Args) (*findResult, error) {
 	var matched *Mock
 	var weights = 0
 	var details = make([]mismatchDetail, 0)
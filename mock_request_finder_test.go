@@ -0,0 +1,79 @@
+package mocha
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/params"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func newFinderStorage(n int) storage {
+	st := newStorage()
+
+	for i := 0; i < n; i++ {
+		st.Save(Get(expect.URLPath(fmt.Sprintf("/resources/%d", i))).
+			Header("x-test", expect.ToEqual("ok")).
+			Reply(reply.OK()).
+			Build())
+	}
+
+	return st
+}
+
+func newFinderArgs(path string) expect.Args {
+	r, _ := http.NewRequest(http.MethodGet, "http://localhost"+path, nil)
+	r.Header.Set("x-test", "ok")
+
+	return expect.Args{RequestInfo: &expect.RequestInfo{Request: r}, Params: params.New()}
+}
+
+func TestFindMockForRequest(t *testing.T) {
+	st := newFinderStorage(10)
+
+	result, err := findMockForRequest(st, newFinderArgs("/resources/5"))
+	assert.Nil(t, err)
+	assert.True(t, result.Matches)
+	assert.Equal(t, st.FetchAll()[5], result.Matched)
+
+	t.Run("should describe mismatches of all mocks when no mock matches", func(t *testing.T) {
+		args := newFinderArgs("/resources/5")
+		args.RequestInfo.Request.Header.Set("x-test", "nok")
+
+		result, err := findMockForRequest(st, args)
+		assert.Nil(t, err)
+		assert.False(t, result.Matches)
+
+		expected, _ := scanMocks(st.FetchEligible(), args)
+		assert.Equal(t, expected.ClosestMatch, result.ClosestMatch)
+		assert.Len(t, result.MismatchDetails, len(expected.MismatchDetails))
+		assert.Len(t, result.MismatchDetails, 19)
+	})
+}
+
+func BenchmarkFindMockForRequest(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		st := newFinderStorage(n)
+		args := newFinderArgs(fmt.Sprintf("/resources/%d", n-1))
+
+		b.Run(fmt.Sprintf("indexed_%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				findMockForRequest(st, args)
+			}
+		})
+
+		b.Run(fmt.Sprintf("linear_%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				scanMocks(st.FetchEligible(), args)
+			}
+		})
+	}
+}
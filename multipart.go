@@ -0,0 +1,83 @@
+package mocha
+
+import (
+	"net/textproto"
+	"net/url"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+)
+
+type (
+	// MultipartForm is the parsed body of multipart/form-data requests.
+	MultipartForm struct {
+		// Fields are the form values, by field name.
+		Fields url.Values
+
+		// Files are the uploaded files, by field name.
+		Files map[string][]*MultipartFile
+	}
+
+	// MultipartFile is a file uploaded with a multipart/form-data request.
+	MultipartFile struct {
+		// Name is the form field name.
+		Name string
+
+		// Filename is the original file name sent by the client.
+		Filename string
+
+		// ContentType is the file part content type.
+		ContentType string
+
+		// Size is the file size in bytes.
+		Size int64
+
+		// Content is the file content.
+		Content []byte
+
+		// Header is the file part MIME header.
+		Header textproto.MIMEHeader
+	}
+)
+
+// File returns the first file uploaded with the given field name, or nil if there is none.
+func (f *MultipartForm) File(name string) *MultipartFile {
+	files := f.Files[name]
+	if len(files) == 0 {
+		return nil
+	}
+
+	return files[0]
+}
+
+// fields returns the file attributes as a map, so they can be matched with expect.JSONPath.
+// The content is converted to string.
+func (f *MultipartFile) fields() map[string]any {
+	return map[string]any{
+		"name":         f.Name,
+		"filename":     f.Filename,
+		"content_type": f.ContentType,
+		"size":         int(f.Size),
+		"content":      string(f.Content),
+	}
+}
+
+// multipartForm returns the parsed multipart form of the request, or nil if it isn't a multipart/form-data request.
+func multipartForm(r *expect.RequestInfo) *MultipartForm {
+	form, _ := r.ParsedBody.(*MultipartForm)
+	return form
+}
+
+// multipartPartMatcher wraps the given matcher so missing multipart fields and files, selected as nil, don't match
+// without calling it.
+func multipartPartMatcher(m expect.Matcher) expect.Matcher {
+	matcher := m
+	matcher.Matches = func(v any, args expect.Args) (bool, error) {
+		if v == nil {
+			return false, nil
+		}
+
+		return m.Matches(v, args)
+	}
+
+	return matcher
+}
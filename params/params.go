@@ -1,6 +1,8 @@
 // Package params implements a simple in-memory key/value store, used internally by Mocha.
 package params
 
+import "sync"
+
 type (
 	// P defines a contract for a generic parameters repository.
 	P interface {
@@ -22,6 +24,7 @@ type (
 
 	inMemoryParams struct {
 		data map[string]any
+		mu   sync.RWMutex
 	}
 )
 
@@ -30,24 +33,44 @@ func New() P {
 	return &inMemoryParams{data: make(map[string]any)}
 }
 
-func (p inMemoryParams) Get(key string) (any, bool) {
+func (p *inMemoryParams) Get(key string) (any, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	val, ok := p.data[key]
 	return val, ok
 }
 
-func (p inMemoryParams) GetAll() map[string]any {
-	return p.data
+func (p *inMemoryParams) GetAll() map[string]any {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	all := make(map[string]any, len(p.data))
+	for k, v := range p.data {
+		all[k] = v
+	}
+
+	return all
 }
 
-func (p inMemoryParams) Set(key string, dep any) {
+func (p *inMemoryParams) Set(key string, dep any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	p.data[key] = dep
 }
 
-func (p inMemoryParams) Remove(key string) {
+func (p *inMemoryParams) Remove(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	delete(p.data, key)
 }
 
-func (p inMemoryParams) Has(key string) bool {
+func (p *inMemoryParams) Has(key string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	_, ok := p.data[key]
 
 	return ok
@@ -0,0 +1,331 @@
+package mocha
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/internal/mimetypes"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+// Redacted is the value used to replace secrets in recorded mocks.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactedHeaders are the headers that are always redacted from recorded mocks.
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+// Record file formats.
+const (
+	RecordFormatJSON = "json"
+	RecordFormatYAML = "yaml"
+)
+
+var slugRegExp = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// RecordConfig defines how request and response pairs are recorded as mock definition files.
+// Responses served by mocks using reply.ProxyReply are always recorded.
+// Recorded files can be loaded later with Mocha.LoadMocksFromDir, replaying the traffic offline.
+type RecordConfig struct {
+	// Dir is the directory where mock definition files are written. It is created if it doesn't exist.
+	Dir string
+
+	// Target is an optional URL. When set, requests that do not match any mock are forwarded to it and recorded.
+	Target string
+
+	// Format defines the format of the generated files: RecordFormatJSON or RecordFormatYAML.
+	// Defaults to RecordFormatJSON.
+	Format string
+
+	// RequestHeaders lists the request headers included in the generated matchers.
+	// Method, URL path and query parameters are always included.
+	RequestHeaders []string
+
+	// RequestBody indicates whether the request body should be included in the generated matchers.
+	RequestBody bool
+
+	// RedactHeaders lists headers, besides DefaultRedactedHeaders, that have their values redacted.
+	// Redacted request headers are matched by presence only.
+	RedactHeaders []string
+
+	// RedactBodyFields lists JSON field names, at any depth, that have their values redacted from
+	// request matchers and response bodies.
+	RedactBodyFields []string
+}
+
+// recorder persists request and response pairs as mock definition files.
+type recorder struct {
+	config        RecordConfig
+	forward       *reply.ProxyReply
+	redactHeaders map[string]bool
+	redactFields  map[string]bool
+	mu            sync.Mutex
+	seq           int
+}
+
+func newRecorder(config RecordConfig) (*recorder, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf("record directory is required")
+	}
+
+	switch config.Format {
+	case "":
+		config.Format = RecordFormatJSON
+	case RecordFormatJSON, RecordFormatYAML:
+	default:
+		return nil, fmt.Errorf("invalid record format %s. use: %s | %s", config.Format, RecordFormatJSON, RecordFormatYAML)
+	}
+
+	rec := &recorder{config: config, redactHeaders: make(map[string]bool), redactFields: make(map[string]bool)}
+
+	if config.Target != "" {
+		u, err := url.Parse(config.Target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid record target %s. reason=%v", config.Target, err)
+		}
+
+		rec.forward = reply.ProxiedFrom(u)
+	}
+
+	for _, h := range DefaultRedactedHeaders {
+		rec.redactHeaders[http.CanonicalHeaderKey(h)] = true
+	}
+
+	for _, h := range config.RedactHeaders {
+		rec.redactHeaders[http.CanonicalHeaderKey(h)] = true
+	}
+
+	for _, f := range config.RedactBodyFields {
+		rec.redactFields[f] = true
+	}
+
+	return rec, nil
+}
+
+// Record writes a new mock definition file based on the given request and response.
+// The response body is read and replaced, so it can still be served.
+func (rec *recorder) Record(entry *RecordedRequest, res *reply.Response) error {
+	var body []byte
+	if res.Body != nil {
+		b, err := io.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+
+		body = b
+		res.Body = bytes.NewReader(b)
+	}
+
+	def := rec.definition(entry, res, body)
+
+	var content []byte
+	var err error
+
+	if rec.config.Format == RecordFormatYAML {
+		content, err = toYAML(def)
+	} else {
+		content, err = json.MarshalIndent(def, "", "  ")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(rec.config.Dir, 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(rec.config.Dir, rec.filename(entry.Request)), content, 0o644)
+}
+
+func (rec *recorder) definition(entry *RecordedRequest, res *reply.Response, body []byte) *mockFileDefinition {
+	r := entry.Request
+	def := &mockFileDefinition{Name: fmt.Sprintf("recorded %s %s", r.Method, r.URL.Path)}
+
+	def.Request.Method = r.Method
+	def.Request.URL = r.URL.Path
+
+	if query := r.URL.Query(); len(query) > 0 {
+		def.Request.Query = make(map[string]any, len(query))
+		for k := range query {
+			def.Request.Query[k] = query.Get(k)
+		}
+	}
+
+	for _, h := range rec.config.RequestHeaders {
+		value := r.Header.Get(h)
+		if value == "" {
+			continue
+		}
+
+		if def.Request.Headers == nil {
+			def.Request.Headers = make(map[string]any)
+		}
+
+		if rec.redactHeaders[http.CanonicalHeaderKey(h)] {
+			def.Request.Headers[h] = map[string]any{"present": true}
+		} else {
+			def.Request.Headers[h] = value
+		}
+	}
+
+	if rec.config.RequestBody {
+		rec.requestBody(def, entry)
+	}
+
+	def.Response.Status = res.Status
+	def.Response.Headers = make(map[string]headerValues)
+
+	for k, values := range res.Header {
+		k = http.CanonicalHeaderKey(k)
+		if k == headers.ContentLength || k == "Date" {
+			continue
+		}
+
+		if rec.redactHeaders[k] {
+			def.Response.Headers[k] = headerValues{Redacted}
+		} else {
+			def.Response.Headers[k] = append(headerValues{}, values...)
+		}
+	}
+
+	var data any
+	switch {
+	case len(body) == 0:
+	case strings.Contains(res.Header.Get(headers.ContentType), mimetypes.JSON) && json.Unmarshal(body, &data) == nil:
+		def.Response.BodyJSON = rec.redact(data)
+	case utf8.Valid(body):
+		def.Response.Body = string(body)
+	default:
+		def.Response.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+
+	return def
+}
+
+func (rec *recorder) requestBody(def *mockFileDefinition, entry *RecordedRequest) {
+	switch body := entry.ParsedBody.(type) {
+	case map[string]any:
+		paths := make(map[string]any, len(body))
+		for k, v := range body {
+			if rec.redactFields[k] || rec.hasRedacted(v) {
+				paths[memberPath(k)] = map[string]any{"present": true}
+			} else {
+				paths[memberPath(k)] = map[string]any{"equal": v}
+			}
+		}
+
+		if len(paths) > 0 {
+			def.Request.Body = map[string]any{"json_path": paths}
+		}
+	case url.Values:
+		def.Request.Form = make(map[string]any, len(body))
+		for k := range body {
+			def.Request.Form[k] = body.Get(k)
+		}
+	case string:
+		def.Request.Body = map[string]any{"equal": body}
+	}
+}
+
+// memberPath returns a JSON path, in bracket notation, that selects the given top-level member.
+// Bracket notation keeps member names with dots, spaces or characters like @ and $ working.
+func memberPath(name string) string {
+	return "['" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(name) + "']"
+}
+
+// redact replaces the values of redacted JSON fields, at any depth.
+func (rec *recorder) redact(v any) any {
+	switch e := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(e))
+		for k, val := range e {
+			if rec.redactFields[k] {
+				m[k] = Redacted
+			} else {
+				m[k] = rec.redact(val)
+			}
+		}
+
+		return m
+	case []any:
+		list := make([]any, len(e))
+		for i, val := range e {
+			list[i] = rec.redact(val)
+		}
+
+		return list
+	}
+
+	return v
+}
+
+// hasRedacted checks if the given JSON value contains any redacted field.
+func (rec *recorder) hasRedacted(v any) bool {
+	switch e := v.(type) {
+	case map[string]any:
+		for k, val := range e {
+			if rec.redactFields[k] || rec.hasRedacted(val) {
+				return true
+			}
+		}
+	case []any:
+		for _, val := range e {
+			if rec.hasRedacted(val) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (rec *recorder) filename(r *http.Request) string {
+	rec.mu.Lock()
+	rec.seq++
+	seq := rec.seq
+	rec.mu.Unlock()
+
+	slug := strings.Trim(slugRegExp.ReplaceAllString(r.URL.Path, "_"), "_")
+	if slug == "" {
+		slug = "root"
+	}
+
+	return fmt.Sprintf("%s_%s_%d_%d.%s",
+		strings.ToLower(r.Method), slug, time.Now().UnixNano(), seq, rec.config.Format)
+}
+
+// toYAML encodes the given value as YAML, using its JSON representation.
+func toYAML(v any) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var data any
+	if err = json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(data)
+}
@@ -0,0 +1,283 @@
+package mocha
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/internal/mimetypes"
+	"github.com/vitorsalgado/mocha/v3/internal/testutil"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func newRecordTarget() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users":
+			w.Header().Add(headers.ContentType, mimetypes.JSON)
+			w.Header().Add("x-token", "secret")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"1","name":"dev","password":"123"}`))
+		case "/binary":
+			w.Header().Add(headers.ContentType, "application/octet-stream")
+			w.Write([]byte{0xff, 0xfe, 0x00, 0x01})
+		default:
+			w.Header().Add(headers.ContentType, mimetypes.TextPlain)
+			w.Write([]byte("hello world"))
+		}
+	}))
+}
+
+func readRecorded(t *testing.T, dir string) []string {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := make([]string, len(files))
+	for i, f := range files {
+		list[i] = filepath.Join(dir, f.Name())
+	}
+
+	return list
+}
+
+func TestRecord(t *testing.T) {
+	target := newRecordTarget()
+	dir := t.TempDir()
+
+	m := New(t, Configure().Record(RecordConfig{
+		Dir:              dir,
+		Target:           target.URL,
+		RequestHeaders:   []string{"authorization", "x-tenant"},
+		RequestBody:      true,
+		RedactHeaders:    []string{"x-token"},
+		RedactBodyFields: []string{"password"}}).Build())
+	m.Start()
+
+	res, err := testutil.PostJSON(m.URL()+"/users?active=true", map[string]any{"name": "dev", "password": "123"}).
+		Header("authorization", "Bearer token").
+		Header("x-tenant", "acme").
+		Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Equal(t, `{"id":"1","name":"dev","password":"123"}`, string(b))
+
+	files := readRecorded(t, dir)
+	assert.Len(t, files, 1)
+
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var def map[string]any
+	assert.NoError(t, json.Unmarshal(content, &def))
+
+	req := def["request"].(map[string]any)
+	assert.Equal(t, http.MethodPost, req["method"])
+	assert.Equal(t, "/users", req["url"])
+	assert.Equal(t, map[string]any{"active": "true"}, req["query"])
+	assert.Equal(t, map[string]any{"authorization": map[string]any{"present": true}, "x-tenant": "acme"}, req["headers"])
+	assert.Equal(t, map[string]any{"json_path": map[string]any{
+		"['name']":     map[string]any{"equal": "dev"},
+		"['password']": map[string]any{"present": true}}}, req["body"])
+
+	resp := def["response"].(map[string]any)
+	assert.Equal(t, float64(http.StatusCreated), resp["status"])
+	assert.Equal(t, Redacted, resp["headers"].(map[string]any)["X-Token"])
+	assert.Equal(t, map[string]any{"id": "1", "name": "dev", "password": Redacted}, resp["body_json"])
+	assert.NotContains(t, string(content), "Bearer token")
+	assert.NotContains(t, string(content), "123")
+
+	t.Run("should record mocks using proxy reply", func(t *testing.T) {
+		m.AddMocks(Get(expect.URLPath("/proxied")).Reply(reply.From(target.URL)))
+
+		res, err := testutil.Get(m.URL() + "/proxied").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "hello world", string(b))
+		assert.Len(t, readRecorded(t, dir), 2)
+	})
+
+	t.Run("should record binary responses", func(t *testing.T) {
+		res, err := testutil.Get(m.URL() + "/binary").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Len(t, readRecorded(t, dir), 3)
+	})
+
+	target.Close()
+	m.Close()
+
+	t.Run("should replay recorded mocks", func(t *testing.T) {
+		m := New(t)
+		m.Start()
+		defer m.Close()
+
+		_, err := m.LoadMocksFromDir(dir)
+		assert.NoError(t, err)
+
+		res, err := testutil.PostJSON(m.URL()+"/users?active=true", map[string]any{"name": "dev", "password": "456"}).
+			Header("authorization", "Bearer other").
+			Header("x-tenant", "acme").
+			Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.JSONEq(t, `{"id":"1","name":"dev","password":"[REDACTED]"}`, string(b))
+
+		res, err = testutil.Get(m.URL() + "/proxied").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, _ = io.ReadAll(res.Body)
+		res.Body.Close()
+
+		assert.Equal(t, "hello world", string(b))
+
+		res, err = testutil.Get(m.URL() + "/binary").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, _ = io.ReadAll(res.Body)
+		res.Body.Close()
+
+		assert.Equal(t, []byte{0xff, 0xfe, 0x00, 0x01}, b)
+	})
+}
+
+func TestRecord_YAML(t *testing.T) {
+	target := newRecordTarget()
+	defer target.Close()
+
+	dir := t.TempDir()
+
+	m := New(t, Configure().Record(RecordConfig{Dir: dir, Target: target.URL, Format: RecordFormatYAML}).Build())
+	m.Start()
+	defer m.Close()
+
+	res, err := testutil.Get(m.URL() + "/hello").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	files := readRecorded(t, dir)
+	assert.Len(t, files, 1)
+	assert.Equal(t, ".yaml", filepath.Ext(files[0]))
+
+	replay := New(t)
+	replay.Start()
+	defer replay.Close()
+
+	_, err = replay.LoadMocksFromFile(files[0])
+	assert.NoError(t, err)
+
+	res, err = testutil.Get(replay.URL() + "/hello").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "hello world", string(b))
+}
+
+func TestRecord_BodyMemberNames(t *testing.T) {
+	target := newRecordTarget()
+	defer target.Close()
+
+	dir := t.TempDir()
+
+	m := New(t, Configure().Record(RecordConfig{Dir: dir, Target: target.URL, RequestBody: true}).Build())
+	m.Start()
+	defer m.Close()
+
+	body := map[string]any{
+		"a.b":        "dots",
+		"first name": "spaces",
+		"@type":      "Person",
+		"$ref":       "#/user",
+		"it's":       `quote \ backslash`,
+	}
+
+	res, err := testutil.PostJSON(m.URL()+"/hello", body).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	files := readRecorded(t, dir)
+	assert.Len(t, files, 1)
+
+	replay := New(t)
+	replay.Start()
+	defer replay.Close()
+
+	_, err = replay.LoadMocksFromFile(files[0])
+	assert.NoError(t, err)
+
+	res, err = testutil.PostJSON(replay.URL()+"/hello", body).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	body["a.b"] = "other"
+
+	res, err = testutil.PostJSON(replay.URL()+"/hello", body).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusTeapot, res.StatusCode)
+}
+
+func TestNewRecorder_Invalid(t *testing.T) {
+	_, err := newRecorder(RecordConfig{})
+	assert.Error(t, err)
+
+	_, err = newRecorder(RecordConfig{Dir: t.TempDir(), Format: "xml"})
+	assert.Error(t, err)
+}
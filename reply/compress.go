@@ -0,0 +1,138 @@
+package reply
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+)
+
+// Supported response content codings.
+const (
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+)
+
+// Compress enables the response body compression with the given content codings, in order of preference.
+// Supported codings are EncodingGzip and EncodingDeflate. When none is given, both are enabled, preferring gzip.
+// The coding is negotiated against the request Accept-Encoding header. When the client doesn't accept any of them,
+// the body is served uncompressed.
+func (rpl *StdReply) Compress(encodings ...string) *StdReply {
+	if len(encodings) == 0 {
+		encodings = []string{EncodingGzip, EncodingDeflate}
+	}
+
+	for _, encoding := range encodings {
+		if encoding != EncodingGzip && encoding != EncodingDeflate {
+			rpl.err = fmt.Errorf("unsupported response content coding %s. use: %s | %s", encoding, EncodingGzip, EncodingDeflate)
+			return rpl
+		}
+	}
+
+	rpl.encodings = encodings
+
+	return rpl
+}
+
+// compress returns a copy of the given Response with the body encoded using the negotiated content coding.
+func (rpl *StdReply) compress(r *http.Request, response *Response) (*Response, error) {
+	res := *response
+	res.Header = response.Header.Clone()
+	res.Header.Add(headers.Vary, headers.AcceptEncoding)
+
+	if r == nil || res.Body == nil {
+		return &res, nil
+	}
+
+	encoding := negotiateEncoding(r.Header.Get(headers.AcceptEncoding), rpl.encodings)
+	if encoding == "" {
+		return &res, nil
+	}
+
+	buf := &bytes.Buffer{}
+
+	var w io.WriteCloser
+	if encoding == EncodingGzip {
+		w = gzip.NewWriter(buf)
+	} else {
+		w = zlib.NewWriter(buf)
+	}
+
+	_, err := io.Copy(w, res.Body)
+
+	// the source body, like a file, is replaced by the compressed one, so it must be closed here.
+	if c, ok := res.Body.(io.Closer); ok {
+		c.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	res.Body = buf
+	res.Header.Set(headers.ContentEncoding, encoding)
+	res.Header.Del(headers.ContentLength)
+
+	return &res, nil
+}
+
+// negotiateEncoding returns the content coding, from the available ones, with the highest quality value in the
+// Accept-Encoding header. Ties are resolved by the order of the available codings.
+// It returns an empty string when none is acceptable.
+func negotiateEncoding(acceptEncoding string, available []string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	accepted := make(map[string]float64)
+
+	for _, entry := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(entry, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+
+		q := 1.0
+
+		for _, param := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(k) != "q" {
+				continue
+			}
+
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				q = f
+			}
+		}
+
+		accepted[coding] = q
+	}
+
+	selected := ""
+	best := 0.0
+
+	for _, encoding := range available {
+		q, ok := accepted[encoding]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+
+		if ok && q > best {
+			selected = encoding
+			best = q
+		}
+	}
+
+	return selected
+}
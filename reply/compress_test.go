@@ -0,0 +1,119 @@
+package reply
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/fs"
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStdReply_Compress(t *testing.T) {
+	testCases := []struct {
+		name           string
+		encodings      []string
+		acceptEncoding string
+		expected       string
+	}{
+		{"gzip", nil, "gzip", EncodingGzip},
+		{"deflate", nil, "deflate", EncodingDeflate},
+		{"prefer gzip on ties", nil, "deflate, gzip", EncodingGzip},
+		{"quality values", nil, "gzip;q=0.5, deflate", EncodingDeflate},
+		{"wildcard", []string{EncodingDeflate}, "*", EncodingDeflate},
+		{"refused", nil, "gzip;q=0, deflate;q=0", ""},
+		{"wildcard refused", nil, "br, *;q=0", ""},
+		{"not enabled", []string{EncodingGzip}, "deflate", ""},
+		{"no accept encoding", nil, "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080", nil)
+			req.Header.Set("accept-encoding", tc.acceptEncoding)
+
+			rpl := OK().
+				Header("content-length", "5").
+				BodyString("hello").
+				Compress(tc.encodings...)
+
+			// the same body must be compressed on every build.
+			for i := 0; i < 2; i++ {
+				res, err := rpl.Build(req, _testMock, nil)
+
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expected, res.Header.Get("content-encoding"))
+				assert.Equal(t, "Accept-Encoding", res.Header.Get("vary"))
+
+				var reader io.Reader = res.Body
+
+				switch tc.expected {
+				case EncodingGzip:
+					reader, err = gzip.NewReader(res.Body)
+					assert.Empty(t, res.Header.Get("content-length"))
+				case EncodingDeflate:
+					reader, err = zlib.NewReader(res.Body)
+					assert.Empty(t, res.Header.Get("content-length"))
+				default:
+					assert.Equal(t, "5", res.Header.Get("content-length"))
+				}
+
+				assert.Nil(t, err)
+
+				b, err := io.ReadAll(reader)
+
+				assert.Nil(t, err)
+				assert.Equal(t, "hello", string(b))
+			}
+		})
+	}
+}
+
+func TestStdReply_CompressUnsupported(t *testing.T) {
+	_, err := OK().Compress("br").Build(_req, _testMock, nil)
+	assert.NotNil(t, err)
+}
+
+type trackedFile struct {
+	fs.File
+	closed bool
+}
+
+func (f *trackedFile) Close() error {
+	f.closed = true
+	return f.File.Close()
+}
+
+type trackedFS struct {
+	fstest.MapFS
+	files []*trackedFile
+}
+
+func (fsys *trackedFS) Open(name string) (fs.File, error) {
+	f, err := fsys.MapFS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tf := &trackedFile{File: f}
+	fsys.files = append(fsys.files, tf)
+
+	return tf, nil
+}
+
+func TestStdReply_CompressClosesFiles(t *testing.T) {
+	fsys := &trackedFS{MapFS: fstest.MapFS{"data.txt": {Data: []byte("hello")}}}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080", nil)
+	req.Header.Set("accept-encoding", "gzip")
+
+	res, err := OK().BodyFS(fsys, "data.txt").Compress().Build(req, _testMock, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, EncodingGzip, res.Header.Get("content-encoding"))
+	assert.Len(t, fsys.files, 1)
+	assert.True(t, fsys.files[0].closed)
+}
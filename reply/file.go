@@ -0,0 +1,91 @@
+package reply
+
+import (
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// TemplateFileExt is the extension of files that are handled as templates by BodyFile and BodyFS.
+const TemplateFileExt = ".tmpl"
+
+// BodyFile defines the response body using the content of the given file.
+// The file is opened on each request and its content is streamed to the client, so changes are served without
+// recreating the mock. The Content-Type header is set based on the file extension, unless it is already set.
+// Files with the TemplateFileExt extension are handled like BodyTemplate, and their Content-Type is based on the
+// extension that comes before it. E.g.: user.json.tmpl is rendered and served as application/json.
+func (rpl *StdReply) BodyFile(name string) *StdReply {
+	return rpl.bodyFile(nil, name)
+}
+
+// BodyFS defines the response body using the content of the named file from the given fs.FS, like an embed.FS.
+// It works like BodyFile.
+func (rpl *StdReply) BodyFS(fsys fs.FS, name string) *StdReply {
+	return rpl.bodyFile(fsys, name)
+}
+
+func (rpl *StdReply) bodyFile(fsys fs.FS, name string) *StdReply {
+	ext := filepath.Ext(name)
+	isTemplate := ext == TemplateFileExt
+	if isTemplate {
+		ext = filepath.Ext(strings.TrimSuffix(name, TemplateFileExt))
+	}
+
+	rpl.contentType = mime.TypeByExtension(ext)
+
+	if isTemplate {
+		b, err := readFile(fsys, name)
+		if err != nil {
+			rpl.err = err
+			return rpl
+		}
+
+		return rpl.BodyTemplate(NewTextTemplate().Name(path.Base(name)).Template(string(b)))
+	}
+
+	info, err := statFile(fsys, name)
+	if err != nil {
+		rpl.err = err
+		return rpl
+	}
+
+	if info.IsDir() {
+		rpl.err = fmt.Errorf("body file %s is a directory", name)
+		return rpl
+	}
+
+	rpl.fsys = fsys
+	rpl.filename = name
+	rpl.bodyType = _bodyFile
+
+	return rpl
+}
+
+// openFile opens the body file. Files are read from the OS file system when no fs.FS is set.
+func (rpl *StdReply) openFile() (fs.File, error) {
+	if rpl.fsys == nil {
+		return os.Open(rpl.filename)
+	}
+
+	return rpl.fsys.Open(rpl.filename)
+}
+
+func readFile(fsys fs.FS, name string) ([]byte, error) {
+	if fsys == nil {
+		return os.ReadFile(name)
+	}
+
+	return fs.ReadFile(fsys, name)
+}
+
+func statFile(fsys fs.FS, name string) (fs.FileInfo, error) {
+	if fsys == nil {
+		return os.Stat(name)
+	}
+
+	return fs.Stat(fsys, name)
+}
@@ -0,0 +1,127 @@
+package reply
+
+import (
+	"io"
+	"net/http"
+	"path"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+)
+
+func TestStdReply_BodyFile(t *testing.T) {
+	read := func(t *testing.T, res *Response) string {
+		b, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if c, ok := res.Body.(io.Closer); ok {
+			assert.Nil(t, c.Close())
+		}
+
+		return string(b)
+	}
+
+	t.Run("should serve the file content on each build", func(t *testing.T) {
+		rpl := OK().BodyFile(path.Join("_testdata", "user.json"))
+
+		for i := 0; i < 2; i++ {
+			res, err := rpl.Build(_req, _testMock, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, http.StatusOK, res.Status)
+			assert.Equal(t, "application/json", res.Header.Get(headers.ContentType))
+			assert.Equal(t, "{\"id\": 1, \"name\": \"dev\"}\n", read(t, res))
+		}
+	})
+
+	t.Run("should keep the content type when it is set", func(t *testing.T) {
+		res, err := OK().
+			Header(headers.ContentType, "application/problem+json").
+			BodyFile(path.Join("_testdata", "user.json")).
+			Build(_req, _testMock, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{"application/problem+json"}, res.Header.Values(headers.ContentType))
+		read(t, res)
+	})
+
+	t.Run("should render template files", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/users/10", nil)
+		req = req.WithContext(ContextWithRequestValues(req.Context(), &RequestValues{
+			ParsedBody: map[string]any{"name": "dev"},
+		}))
+
+		res, err := Created().BodyFile(path.Join("_testdata", "user.json.tmpl")).Build(req, _testMock, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, http.StatusCreated, res.Status)
+		assert.Equal(t, "application/json", res.Header.Get(headers.ContentType))
+		assert.Equal(t, "{\"id\": \"10\", \"name\": \"dev\"}\n", read(t, res))
+	})
+
+	t.Run("should return error when the file does not exist", func(t *testing.T) {
+		_, err := OK().BodyFile(path.Join("_testdata", "not_found.json")).Build(_req, _testMock, nil)
+		assert.Error(t, err)
+
+		_, err = OK().BodyFile(path.Join("_testdata", "not_found.json.tmpl")).Build(_req, _testMock, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error when the file is a directory", func(t *testing.T) {
+		_, err := OK().BodyFile("_testdata").Build(_req, _testMock, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestStdReply_BodyFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"static/index.html":  {Data: []byte("<h1>hello</h1>")},
+		"static/hello.txt":   {Data: []byte("hello")},
+		"static/hello.tmpl":  {Data: []byte(`hello {{ .Query "name" }}`)},
+		"static/no_ext_file": {Data: []byte("data")},
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/hello?name=dev", nil)
+
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"static/index.html", "text/html; charset=utf-8", "<h1>hello</h1>"},
+		{"static/hello.txt", "text/plain; charset=utf-8", "hello"},
+		{"static/hello.tmpl", "", "hello dev"},
+		{"static/no_ext_file", "", "data"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := OK().BodyFS(fsys, tc.name).Build(req, _testMock, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := io.ReadAll(res.Body)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.contentType, res.Header.Get(headers.ContentType))
+			assert.Equal(t, tc.body, string(b))
+		})
+	}
+
+	t.Run("should return error when the file does not exist", func(t *testing.T) {
+		_, err := OK().BodyFS(fsys, "static/not_found.txt").Build(req, _testMock, nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,131 @@
+package reply
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/internal/mimetypes"
+	"github.com/vitorsalgado/mocha/v3/params"
+)
+
+type (
+	// GraphQLReply represents a GraphQL response stub, wrapping data and errors in the standard response format.
+	// Use GraphQL to init a new GraphQLReply.
+	GraphQLReply struct {
+		status     int
+		header     http.Header
+		data       any
+		hasData    bool
+		errors     []GraphQLError
+		extensions map[string]any
+		delay      time.Duration
+	}
+
+	// GraphQLError is a GraphQL response error.
+	GraphQLError struct {
+		Message    string            `json:"message"`
+		Locations  []GraphQLLocation `json:"locations,omitempty"`
+		Path       []any             `json:"path,omitempty"`
+		Extensions map[string]any    `json:"extensions,omitempty"`
+	}
+
+	// GraphQLLocation is a location in the GraphQL query associated with an error.
+	GraphQLLocation struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	}
+)
+
+// GraphQL inits a new GraphQLReply with the status http.StatusOK and the content type application/json.
+func GraphQL() *GraphQLReply {
+	header := make(http.Header)
+	header.Add(headers.ContentType, mimetypes.JSON)
+
+	return &GraphQLReply{
+		status:     http.StatusOK,
+		header:     header,
+		errors:     make([]GraphQLError, 0),
+		extensions: make(map[string]any)}
+}
+
+// GraphQLData inits a new GraphQLReply with the given data.
+func GraphQLData(data any) *GraphQLReply {
+	return GraphQL().Data(data)
+}
+
+// Data sets the response data. The data entry is only present in the response when it is set,
+// so a nil value results in "data": null.
+func (r *GraphQLReply) Data(data any) *GraphQLReply {
+	r.data = data
+	r.hasData = true
+	return r
+}
+
+// Error adds an error with the given message and response path.
+func (r *GraphQLReply) Error(message string, path ...any) *GraphQLReply {
+	return r.Errors(GraphQLError{Message: message, Path: path})
+}
+
+// Errors adds the given errors to the response.
+func (r *GraphQLReply) Errors(errors ...GraphQLError) *GraphQLReply {
+	r.errors = append(r.errors, errors...)
+	return r
+}
+
+// Extension adds an entry to the response extensions.
+func (r *GraphQLReply) Extension(key string, value any) *GraphQLReply {
+	r.extensions[key] = value
+	return r
+}
+
+// Status sets the HTTP status code.
+func (r *GraphQLReply) Status(status int) *GraphQLReply {
+	r.status = status
+	return r
+}
+
+// Header adds a header to the response.
+func (r *GraphQLReply) Header(key, value string) *GraphQLReply {
+	r.header.Add(key, value)
+	return r
+}
+
+// Delay sets a delay time before serving the stub Response.
+func (r *GraphQLReply) Delay(duration time.Duration) *GraphQLReply {
+	r.delay = duration
+	return r
+}
+
+// Build builds a Response with the GraphQL payload encoded as JSON.
+func (r *GraphQLReply) Build(_ *http.Request, _ M, _ params.P) (*Response, error) {
+	payload := make(map[string]any)
+
+	if r.hasData {
+		payload["data"] = r.data
+	}
+
+	if len(r.errors) > 0 {
+		payload["errors"] = r.errors
+	}
+
+	if len(r.extensions) > 0 {
+		payload["extensions"] = r.extensions
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{
+		Status:  r.status,
+		Header:  r.header.Clone(),
+		Cookies: make([]*http.Cookie, 0),
+		Body:    bytes.NewReader(b),
+		Delay:   r.delay,
+		Mappers: make([]ResponseMapper, 0),
+	}, nil
+}
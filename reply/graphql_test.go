@@ -0,0 +1,51 @@
+package reply
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		reply    *GraphQLReply
+		expected string
+	}{
+		{"data", GraphQLData(map[string]any{"user": map[string]any{"id": 1}}), `{"data":{"user":{"id":1}}}`},
+		{"null data", GraphQL().Data(nil), `{"data":null}`},
+		{"data with errors",
+			GraphQLData(map[string]any{"user": nil}).Error("user not found", "user"),
+			`{"data":{"user":null},"errors":[{"message":"user not found","path":["user"]}]}`},
+		{"errors only",
+			GraphQL().Errors(GraphQLError{
+				Message:    "syntax error",
+				Locations:  []GraphQLLocation{{Line: 1, Column: 2}},
+				Extensions: map[string]any{"code": "GRAPHQL_PARSE_FAILED"}}),
+			`{"errors":[{"message":"syntax error","locations":[{"line":1,"column":2}],"extensions":{"code":"GRAPHQL_PARSE_FAILED"}}]}`},
+		{"extensions", GraphQLData(true).Extension("cost", 1), `{"data":true,"extensions":{"cost":1}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.reply.Build(nil, nil, nil)
+			assert.Nil(t, err)
+
+			b, err := io.ReadAll(res.Body)
+			assert.Nil(t, err)
+			assert.JSONEq(t, tc.expected, string(b))
+			assert.Equal(t, http.StatusOK, res.Status)
+			assert.Equal(t, "application/json", res.Header.Get("content-type"))
+		})
+	}
+}
+
+func TestGraphQL_StatusAndHeaders(t *testing.T) {
+	res, err := GraphQL().Error("unauthorized").Status(http.StatusUnauthorized).Header("x-test", "ok").Build(nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.Status)
+	assert.Equal(t, "ok", res.Header.Get("x-test"))
+}
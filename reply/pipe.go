@@ -0,0 +1,33 @@
+package reply
+
+import (
+	"io"
+	"sync"
+)
+
+// lazyPipe is an io.ReadCloser that runs its producer, in a new goroutine, on the first Read.
+// Responses that are built but never written, like when a mapper fails, don't leave producers blocked.
+// Closing it before the first Read means the producer never runs.
+type lazyPipe struct {
+	once    sync.Once
+	produce func(w *io.PipeWriter)
+	pr      *io.PipeReader
+	pw      *io.PipeWriter
+}
+
+// newLazyPipe creates a lazyPipe with the given producer, that must close the writer when it is done.
+func newLazyPipe(produce func(w *io.PipeWriter)) *lazyPipe {
+	pr, pw := io.Pipe()
+	return &lazyPipe{produce: produce, pr: pr, pw: pw}
+}
+
+func (p *lazyPipe) Read(b []byte) (int, error) {
+	p.once.Do(func() { go p.produce(p.pw) })
+	return p.pr.Read(b)
+}
+
+// Close closes the reading side, so a running producer fails its next write and stops.
+func (p *lazyPipe) Close() error {
+	p.once.Do(func() { p.pw.Close() })
+	return p.pr.Close()
+}
@@ -121,10 +121,11 @@ func (r *ProxyReply) Build(req *http.Request, _ M, _ params.P) (*Response, error
 		return nil, err
 	}
 
+	// cookies are kept in the Set-Cookie headers, as received from the target.
 	response := &Response{
 		Status:  res.StatusCode,
 		Header:  res.Header,
-		Cookies: res.Cookies(),
+		Cookies: make([]*http.Cookie, 0),
 		Delay:   r.delay,
 	}
 
@@ -3,11 +3,16 @@ package reply
 import (
 	"bytes"
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
 	"github.com/vitorsalgado/mocha/v3/params"
 )
 
@@ -24,13 +29,41 @@ type (
 		Build(*http.Request, M, params.P) (*Response, error)
 	}
 
+	// Upgrader is implemented by replies that take over the HTTP connection, like WebSocketReply.
+	// The mock server calls Upgrade, instead of Build, and the reply is responsible for writing the response.
+	Upgrader interface {
+		Reply
+
+		// Upgrade serves the request using the given http.ResponseWriter.
+		// It blocks until the connection is done.
+		Upgrade(http.ResponseWriter, *http.Request, M, params.P) error
+	}
+
 	// StdReply holds the configuration on how the Response should be built.
 	StdReply struct {
-		response *Response
-		bodyType bodyType
+		response        *Response
+		bodyType        bodyType
+		body            []byte
+		template        Template
+		statusTemplate  Template
+		headerTemplates []*headerTemplate
+		cookieTemplates []*cookieTemplate
+		fsys            fs.FS
+		filename        string
+		contentType     string
+		model           any
+		encodings       []string
+		err             error
+	}
+
+	headerTemplate struct {
+		key      string
+		template Template
+	}
+
+	cookieTemplate struct {
+		cookie   http.Cookie
 		template Template
-		model    any
-		err      error
 	}
 
 	bodyType int
@@ -38,7 +71,9 @@ type (
 
 const (
 	_bodyDefault bodyType = iota
+	_bodyBytes
 	_bodyTemplate
+	_bodyFile
 )
 
 // New creates a new StdReply. Prefer to use factory functions for each status code.
@@ -119,18 +154,43 @@ func (rpl *StdReply) Status(status int) *StdReply {
 	return rpl
 }
 
+// StatusTemplate defines the HTTP status code using a template, rendered on each request.
+// The template output must be a valid status code, like {{ if .Body.id }}200{{ else }}400{{ end }}.
+// It accepts a string or a reply.Template implementation. If a different type is provided, it panics.
+func (rpl *StdReply) StatusTemplate(template any) *StdReply {
+	rpl.statusTemplate = rpl.compileTemplate("StatusTemplate", template)
+	return rpl
+}
+
 // Header adds a header to the Response.
 func (rpl *StdReply) Header(key, value string) *StdReply {
 	rpl.response.Header.Add(key, value)
 	return rpl
 }
 
+// HeaderTemplate adds a header to the Response with its value defined by a template, rendered on each request.
+// E.g.: HeaderTemplate("Location", "/users/{{ .Body.id }}").
+// It accepts a string or a reply.Template implementation. If a different type is provided, it panics.
+func (rpl *StdReply) HeaderTemplate(key string, template any) *StdReply {
+	rpl.headerTemplates = append(rpl.headerTemplates,
+		&headerTemplate{key: key, template: rpl.compileTemplate("HeaderTemplate", template)})
+	return rpl
+}
+
 // Cookie adds a http.Cookie to the Response.
 func (rpl *StdReply) Cookie(cookie http.Cookie) *StdReply {
 	rpl.response.Cookies = append(rpl.response.Cookies, &cookie)
 	return rpl
 }
 
+// CookieTemplate adds a http.Cookie to the Response with its value defined by a template, rendered on each request.
+// It accepts a string or a reply.Template implementation. If a different type is provided, it panics.
+func (rpl *StdReply) CookieTemplate(cookie http.Cookie, template any) *StdReply {
+	rpl.cookieTemplates = append(rpl.cookieTemplates,
+		&cookieTemplate{cookie: cookie, template: rpl.compileTemplate("CookieTemplate", template)})
+	return rpl
+}
+
 // ExpireCookie expires a cookie.
 func (rpl *StdReply) ExpireCookie(cookie http.Cookie) *StdReply {
 	cookie.MaxAge = -1
@@ -138,16 +198,18 @@ func (rpl *StdReply) ExpireCookie(cookie http.Cookie) *StdReply {
 	return rpl
 }
 
-// Body defines the response body using a []byte,
+// Body defines the response body using a []byte.
+// The same content is served on every request.
 func (rpl *StdReply) Body(value []byte) *StdReply {
-	rpl.response.Body = bytes.NewReader(value)
+	rpl.body = value
+	rpl.bodyType = _bodyBytes
+
 	return rpl
 }
 
 // BodyString defines the response body using a string.
 func (rpl *StdReply) BodyString(value string) *StdReply {
-	rpl.response.Body = strings.NewReader(value)
-	return rpl
+	return rpl.Body([]byte(value))
 }
 
 // BodyJSON defines the response body encoding the given value using json.Encoder.
@@ -159,31 +221,34 @@ func (rpl *StdReply) BodyJSON(data any) *StdReply {
 		return rpl
 	}
 
-	rpl.response.Body = buf
+	return rpl.Body(buf.Bytes())
+}
+
+// BodyXML defines the response body encoding the given value using xml.Marshal.
+func (rpl *StdReply) BodyXML(data any) *StdReply {
+	b, err := xml.Marshal(data)
+	if err != nil {
+		rpl.err = err
+		return rpl
+	}
 
-	return rpl
+	return rpl.Body(b)
 }
 
 // BodyReader defines the response body using the given io.Reader.
+// The reader is consumed by the first request, so later requests receive an empty body.
+// Use Body to serve the same content on every request.
 func (rpl *StdReply) BodyReader(reader io.Reader) *StdReply {
 	rpl.response.Body = reader
+	rpl.bodyType = _bodyDefault
+
 	return rpl
 }
 
 // BodyTemplate defines the response body using a template.
 // It accepts a string or a reply.Template implementation. If a different type is provided, it panics.
 func (rpl *StdReply) BodyTemplate(template any) *StdReply {
-	switch e := template.(type) {
-	case string:
-		rpl.template = NewTextTemplate().Template(e)
-	case Template:
-		rpl.err = e.Compile()
-		rpl.template = e
-
-	default:
-		panic(".BodyTemplate() parameter must be: string | reply.Template")
-	}
-
+	rpl.template = rpl.compileTemplate("BodyTemplate", template)
 	rpl.bodyType = _bodyTemplate
 
 	return rpl
@@ -208,22 +273,129 @@ func (rpl *StdReply) Map(mapper ResponseMapper) *StdReply {
 }
 
 // Build builds a Response based on StdReply definition.
-func (rpl *StdReply) Build(r *http.Request, _ M, _ params.P) (*Response, error) {
+func (rpl *StdReply) Build(r *http.Request, m M, p params.P) (*Response, error) {
 	if rpl.err != nil {
 		return nil, rpl.err
 	}
 
-	switch rpl.bodyType {
-	case _bodyTemplate:
+	res := rpl.response
+
+	if rpl.bodyType != _bodyDefault ||
+		rpl.contentType != "" ||
+		rpl.statusTemplate != nil ||
+		len(rpl.headerTemplates) > 0 ||
+		len(rpl.cookieTemplates) > 0 {
+		var err error
+		res, err = rpl.render(newTemplateData(r, m, p, rpl.model))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(rpl.encodings) > 0 {
+		return rpl.compress(r, res)
+	}
+
+	return res, nil
+}
+
+// render returns a copy of the Response with the templated parts rendered using the given data,
+// a new reader for the body bytes and the body file opened.
+func (rpl *StdReply) render(data *TemplateData) (*Response, error) {
+	res := *rpl.response
+	res.Header = rpl.response.Header.Clone()
+	res.Cookies = append(make([]*http.Cookie, 0, len(rpl.response.Cookies)), rpl.response.Cookies...)
+
+	if rpl.bodyType == _bodyBytes {
+		res.Body = bytes.NewReader(rpl.body)
+	}
+
+	if rpl.bodyType == _bodyTemplate {
 		buf := &bytes.Buffer{}
-		model := &TemplateData{Request: r, Data: rpl.model}
-		err := rpl.template.Parse(buf, model)
+		if err := rpl.template.Parse(buf, data); err != nil {
+			return nil, err
+		}
+
+		res.Body = buf
+	}
+
+	if rpl.statusTemplate != nil {
+		value, err := renderTemplate(rpl.statusTemplate, data)
 		if err != nil {
 			return nil, err
 		}
 
-		rpl.response.Body = buf
+		status, err := strconv.Atoi(value)
+		if err != nil || status < 100 || status > 999 {
+			return nil, fmt.Errorf("status template must render a valid http status code. got %q", value)
+		}
+
+		res.Status = status
+	}
+
+	for _, h := range rpl.headerTemplates {
+		value, err := renderTemplate(h.template, data)
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", h.key, err)
+		}
+
+		res.Header.Add(h.key, value)
+	}
+
+	for _, c := range rpl.cookieTemplates {
+		value, err := renderTemplate(c.template, data)
+		if err != nil {
+			return nil, fmt.Errorf("cookie %s: %w", c.cookie.Name, err)
+		}
+
+		cookie := c.cookie
+		cookie.Value = value
+		res.Cookies = append(res.Cookies, &cookie)
+	}
+
+	if rpl.contentType != "" && res.Header.Get(headers.ContentType) == "" {
+		res.Header.Set(headers.ContentType, rpl.contentType)
+	}
+
+	if rpl.bodyType == _bodyFile {
+		f, err := rpl.openFile()
+		if err != nil {
+			return nil, err
+		}
+
+		res.Body = f
+	}
+
+	return &res, nil
+}
+
+// compileTemplate returns a compiled Template from a string or a reply.Template.
+// Compilation errors are returned by Build. It panics if a different type is provided.
+func (rpl *StdReply) compileTemplate(method string, template any) Template {
+	var t Template
+
+	switch e := template.(type) {
+	case string:
+		t = NewTextTemplate().Template(e)
+	case Template:
+		t = e
+	default:
+		panic(fmt.Sprintf(".%s() parameter must be: string | reply.Template", method))
+	}
+
+	if err := t.Compile(); err != nil && rpl.err == nil {
+		rpl.err = err
+	}
+
+	return t
+}
+
+// renderTemplate executes the Template and returns the output without leading and trailing white spaces.
+func renderTemplate(t Template, data *TemplateData) (string, error) {
+	buf := &strings.Builder{}
+	if err := t.Parse(buf, data); err != nil {
+		return "", err
 	}
 
-	return rpl.response, nil
+	return strings.TrimSpace(buf.String()), nil
 }
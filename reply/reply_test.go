@@ -2,6 +2,7 @@ package reply
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"io"
 	"net/http"
 	"os"
@@ -74,17 +75,21 @@ func TestReply(t *testing.T) {
 }
 
 func TestStdReply_BodyString(t *testing.T) {
-	res, err := New().
+	rpl := New().
 		Status(http.StatusCreated).
-		BodyString("text").
-		Build(_req, _testMock, nil)
+		BodyString("text")
 
-	assert.Nil(t, err)
+	// the same body must be served on every build.
+	for i := 0; i < 2; i++ {
+		res, err := rpl.Build(_req, _testMock, nil)
 
-	b, err := io.ReadAll(res.Body)
+		assert.Nil(t, err)
 
-	assert.Nil(t, err)
-	assert.Equal(t, "text", string(b))
+		b, err := io.ReadAll(res.Body)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "text", string(b))
+	}
 }
 
 func TestStdReply_BodyJSON(t *testing.T) {
@@ -120,6 +125,37 @@ func TestStdReply_BodyJSON(t *testing.T) {
 	})
 }
 
+func TestStdReply_BodyXML(t *testing.T) {
+	type user struct {
+		XMLName xml.Name `xml:"user"`
+		ID      int      `xml:"id,attr"`
+		Name    string   `xml:"name"`
+	}
+
+	t.Run("should convert struct to xml", func(t *testing.T) {
+		res, err := New().
+			Status(http.StatusOK).
+			BodyXML(user{ID: 1, Name: "dev"}).
+			Build(_req, _testMock, nil)
+
+		assert.Nil(t, err)
+
+		b, err := io.ReadAll(res.Body)
+
+		assert.Nil(t, err)
+		assert.Equal(t, `<user id="1"><name>dev</name></user>`, string(b))
+	})
+
+	t.Run("should report conversion error", func(t *testing.T) {
+		res, err := New().
+			BodyXML(make(chan int)).
+			Build(_req, _testMock, nil)
+
+		assert.Nil(t, res)
+		assert.NotNil(t, err)
+	})
+}
+
 func TestStdReply_BodyReader(t *testing.T) {
 	wd, _ := os.Getwd()
 	f, err := os.Open(path.Join(wd, "_testdata", "data.txt"))
@@ -0,0 +1,41 @@
+package reply
+
+import (
+	"context"
+	"net/http"
+)
+
+type requestValuesKey struct{}
+
+// RequestValues holds request related values computed by the mock server, like the parsed body.
+// The server adds them to the request context before building replies.
+type RequestValues struct {
+	// ParsedBody is the request body parsed by the matching RequestBodyParser.
+	ParsedBody any
+
+	// RawBody is the request body content.
+	RawBody []byte
+
+	// ScenarioName is the name of the scenario of the matched mock, if any.
+	ScenarioName string
+
+	// ScenarioState is the state of the scenario after the matched mock was served.
+	ScenarioState string
+}
+
+// ContextWithRequestValues returns a copy of the given context with the RequestValues.
+func ContextWithRequestValues(ctx context.Context, values *RequestValues) context.Context {
+	return context.WithValue(ctx, requestValuesKey{}, values)
+}
+
+// RequestValuesFromRequest returns the RequestValues added to the request context by the mock server.
+// It returns empty RequestValues when there is none.
+func RequestValuesFromRequest(r *http.Request) *RequestValues {
+	if r != nil {
+		if values, ok := r.Context().Value(requestValuesKey{}).(*RequestValues); ok {
+			return values
+		}
+	}
+
+	return &RequestValues{}
+}
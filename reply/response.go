@@ -15,8 +15,14 @@ type (
 		Header  http.Header
 		Cookies []*http.Cookie
 		Body    io.Reader
+		Trailer http.Header
 		Delay   time.Duration
 		Mappers []ResponseMapper
+
+		// Flush flushes every body write to the client, instead of buffering it.
+		// It is meant for streaming replies, like SSEReply and StreamReply.
+		// Flushed responses don't have a Content-Length and are sent using chunked encoding.
+		Flush bool
 	}
 
 	// ResponseMapperArgs represents the expected arguments for every ResponseMapper.
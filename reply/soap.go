@@ -0,0 +1,271 @@
+package reply
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/params"
+)
+
+// SOAPVersion is the SOAP protocol version.
+type SOAPVersion int
+
+// SOAP protocol versions.
+const (
+	SOAP11 SOAPVersion = iota
+	SOAP12
+)
+
+// SOAP envelope namespaces.
+const (
+	SOAP11Namespace = "http://schemas.xmlsoap.org/soap/envelope/"
+	SOAP12Namespace = "http://www.w3.org/2003/05/soap-envelope"
+)
+
+// SOAP fault codes, using the SOAP 1.2 names.
+// SOAP 1.1 envelopes use their equivalents: Client for SOAPFaultSender and Server for SOAPFaultReceiver.
+const (
+	SOAPFaultVersionMismatch = "VersionMismatch"
+	SOAPFaultMustUnderstand  = "MustUnderstand"
+	SOAPFaultSender          = "Sender"
+	SOAPFaultReceiver        = "Receiver"
+)
+
+type (
+	// SOAPReply represents a SOAP response stub, wrapping the body content in a SOAP envelope.
+	// Use SOAP to init a new SOAPReply.
+	SOAPReply struct {
+		version SOAPVersion
+		status  int
+		header  http.Header
+		headers []any
+		body    []any
+		fault   *SOAPFault
+		delay   time.Duration
+	}
+
+	// SOAPFault defines a SOAP fault.
+	SOAPFault struct {
+		// Code is the fault code, like SOAPFaultSender.
+		Code string
+
+		// Reason is the human-readable fault description.
+		Reason string
+
+		// Detail is the application specific fault detail. Values are encoded using xml.Marshal,
+		// except strings and []byte, that are written as is.
+		Detail any
+	}
+)
+
+// SOAP inits a new SOAP 1.1 SOAPReply with the status http.StatusOK.
+func SOAP() *SOAPReply {
+	return &SOAPReply{
+		version: SOAP11,
+		status:  http.StatusOK,
+		header:  make(http.Header),
+		headers: make([]any, 0),
+		body:    make([]any, 0)}
+}
+
+// SOAPFaultReply inits a new SOAP 1.1 SOAPReply with a fault and the status http.StatusInternalServerError.
+func SOAPFaultReply(code, reason string) *SOAPReply {
+	return SOAP().Fault(SOAPFault{Code: code, Reason: reason})
+}
+
+// Version sets the SOAP protocol version, which defines the envelope namespace, the fault format and the content type.
+func (r *SOAPReply) Version(version SOAPVersion) *SOAPReply {
+	r.version = version
+	return r
+}
+
+// Status sets the HTTP status code.
+func (r *SOAPReply) Status(status int) *SOAPReply {
+	r.status = status
+	return r
+}
+
+// Header adds an HTTP header to the response.
+func (r *SOAPReply) Header(key, value string) *SOAPReply {
+	r.header.Add(key, value)
+	return r
+}
+
+// EnvelopeHeader adds an entry to the SOAP envelope header.
+// Values are encoded using xml.Marshal, except strings and []byte, that are written as is.
+func (r *SOAPReply) EnvelopeHeader(v any) *SOAPReply {
+	r.headers = append(r.headers, v)
+	return r
+}
+
+// Body adds an entry to the SOAP envelope body, usually the operation response.
+// Values are encoded using xml.Marshal, except strings and []byte, that are written as is.
+func (r *SOAPReply) Body(v any) *SOAPReply {
+	r.body = append(r.body, v)
+	return r
+}
+
+// Fault sets a fault as the SOAP envelope body content.
+// The status is set to http.StatusInternalServerError, as required by SOAP. Call Status afterwards to change it.
+func (r *SOAPReply) Fault(fault SOAPFault) *SOAPReply {
+	r.fault = &fault
+	r.status = http.StatusInternalServerError
+	return r
+}
+
+// Delay sets a delay time before serving the stub Response.
+func (r *SOAPReply) Delay(duration time.Duration) *SOAPReply {
+	r.delay = duration
+	return r
+}
+
+// Build builds a Response with the SOAP envelope.
+func (r *SOAPReply) Build(_ *http.Request, _ M, _ params.P) (*Response, error) {
+	b, err := r.envelope()
+	if err != nil {
+		return nil, err
+	}
+
+	header := r.header.Clone()
+	if header.Get(headers.ContentType) == "" {
+		header.Set(headers.ContentType, r.contentType())
+	}
+
+	return &Response{
+		Status:  r.status,
+		Header:  header,
+		Cookies: make([]*http.Cookie, 0),
+		Body:    bytes.NewReader(b),
+		Delay:   r.delay,
+		Mappers: make([]ResponseMapper, 0),
+	}, nil
+}
+
+func (r *SOAPReply) contentType() string {
+	if r.version == SOAP12 {
+		return "application/soap+xml; charset=utf-8"
+	}
+
+	return "text/xml; charset=utf-8"
+}
+
+func (r *SOAPReply) namespace() string {
+	if r.version == SOAP12 {
+		return SOAP12Namespace
+	}
+
+	return SOAP11Namespace
+}
+
+func (r *SOAPReply) envelope() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+	buf.WriteString(fmt.Sprintf(`<soap:Envelope xmlns:soap="%s">`, r.namespace()))
+
+	if len(r.headers) > 0 {
+		buf.WriteString("<soap:Header>")
+
+		for _, v := range r.headers {
+			if err := writeXML(buf, v); err != nil {
+				return nil, err
+			}
+		}
+
+		buf.WriteString("</soap:Header>")
+	}
+
+	buf.WriteString("<soap:Body>")
+
+	if r.fault != nil {
+		if err := r.writeFault(buf); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, v := range r.body {
+			if err := writeXML(buf, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	buf.WriteString("</soap:Body></soap:Envelope>")
+
+	return buf.Bytes(), nil
+}
+
+func (r *SOAPReply) writeFault(buf *bytes.Buffer) error {
+	buf.WriteString("<soap:Fault>")
+
+	if r.version == SOAP12 {
+		buf.WriteString("<soap:Code><soap:Value>soap:")
+		xml.EscapeText(buf, []byte(r.fault.Code))
+		buf.WriteString("</soap:Value></soap:Code>")
+		buf.WriteString(`<soap:Reason><soap:Text xml:lang="en">`)
+		xml.EscapeText(buf, []byte(r.fault.Reason))
+		buf.WriteString("</soap:Text></soap:Reason>")
+
+		if r.fault.Detail != nil {
+			buf.WriteString("<soap:Detail>")
+			if err := writeXML(buf, r.fault.Detail); err != nil {
+				return err
+			}
+			buf.WriteString("</soap:Detail>")
+		}
+	} else {
+		buf.WriteString("<faultcode>soap:")
+		xml.EscapeText(buf, []byte(soap11FaultCode(r.fault.Code)))
+		buf.WriteString("</faultcode><faultstring>")
+		xml.EscapeText(buf, []byte(r.fault.Reason))
+		buf.WriteString("</faultstring>")
+
+		if r.fault.Detail != nil {
+			buf.WriteString("<detail>")
+			if err := writeXML(buf, r.fault.Detail); err != nil {
+				return err
+			}
+			buf.WriteString("</detail>")
+		}
+	}
+
+	buf.WriteString("</soap:Fault>")
+
+	return nil
+}
+
+// soap11FaultCode converts SOAP 1.2 fault codes to their SOAP 1.1 equivalents.
+func soap11FaultCode(code string) string {
+	switch code {
+	case SOAPFaultSender:
+		return "Client"
+	case SOAPFaultReceiver:
+		return "Server"
+	}
+
+	return code
+}
+
+// writeXML writes strings and []byte as is, and encodes other values using xml.Marshal.
+func writeXML(buf *bytes.Buffer, v any) error {
+	switch e := v.(type) {
+	case string:
+		buf.WriteString(strings.TrimSpace(e))
+		return nil
+	case []byte:
+		buf.Write(e)
+		return nil
+	}
+
+	b, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	buf.Write(b)
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package reply
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type soapUser struct {
+	XMLName xml.Name `xml:"GetUserResponse"`
+	Name    string   `xml:"name"`
+}
+
+func readSOAP(t *testing.T, rep *SOAPReply) (*Response, string) {
+	res, err := rep.Build(nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res, string(b)
+}
+
+func TestSOAP(t *testing.T) {
+	res, body := readSOAP(t, SOAP().
+		EnvelopeHeader(`<Session>abc</Session>`).
+		Body(soapUser{Name: "dev"}))
+
+	assert.Equal(t, http.StatusOK, res.Status)
+	assert.Equal(t, "text/xml; charset=utf-8", res.Header.Get("content-type"))
+	assert.Equal(t, xml.Header+
+		`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">`+
+		`<soap:Header><Session>abc</Session></soap:Header>`+
+		`<soap:Body><GetUserResponse><name>dev</name></GetUserResponse></soap:Body>`+
+		`</soap:Envelope>`, body)
+
+	res, body = readSOAP(t, SOAP().Version(SOAP12).Header("x-test", "ok").Body(`<Pong/>`))
+
+	assert.Equal(t, "application/soap+xml; charset=utf-8", res.Header.Get("content-type"))
+	assert.Equal(t, "ok", res.Header.Get("x-test"))
+	assert.Equal(t, xml.Header+
+		`<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope">`+
+		`<soap:Body><Pong/></soap:Body>`+
+		`</soap:Envelope>`, body)
+}
+
+func TestSOAP_Fault(t *testing.T) {
+	res, body := readSOAP(t, SOAP().Fault(SOAPFault{Code: SOAPFaultSender, Reason: "invalid <id>", Detail: `<code>10</code>`}))
+
+	assert.Equal(t, http.StatusInternalServerError, res.Status)
+	assert.Equal(t, xml.Header+
+		`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault>`+
+		`<faultcode>soap:Client</faultcode><faultstring>invalid &lt;id&gt;</faultstring><detail><code>10</code></detail>`+
+		`</soap:Fault></soap:Body></soap:Envelope>`, body)
+
+	res, body = readSOAP(t, SOAPFaultReply(SOAPFaultReceiver, "unavailable").Version(SOAP12).Status(http.StatusServiceUnavailable))
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.Status)
+	assert.Equal(t, xml.Header+
+		`<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body><soap:Fault>`+
+		`<soap:Code><soap:Value>soap:Receiver</soap:Value></soap:Code>`+
+		`<soap:Reason><soap:Text xml:lang="en">unavailable</soap:Text></soap:Reason>`+
+		`</soap:Fault></soap:Body></soap:Envelope>`, body)
+}
+
+func TestSOAP_EncodingError(t *testing.T) {
+	_, err := SOAP().Body(make(chan int)).Build(nil, nil, nil)
+	assert.NotNil(t, err)
+}
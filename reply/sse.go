@@ -0,0 +1,144 @@
+package reply
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/internal/mimetypes"
+	"github.com/vitorsalgado/mocha/v3/params"
+)
+
+type (
+	// SSEReply represents a Server-Sent Events response stub.
+	// Events are written in sequence, each one flushed to the client as soon as it is written.
+	// Use SSE to init a new SSEReply.
+	SSEReply struct {
+		status   int
+		header   http.Header
+		events   []SSEEvent
+		keepOpen bool
+	}
+
+	// SSEEvent defines one Server-Sent Event.
+	SSEEvent struct {
+		// ID sets the event id field.
+		ID string
+
+		// Event sets the event type field.
+		Event string
+
+		// Data sets the event data. Multiline values are sent as multiple data fields.
+		Data string
+
+		// Retry sets the client reconnection time.
+		Retry time.Duration
+
+		// Delay is the time to wait before sending the event.
+		Delay time.Duration
+	}
+)
+
+// SSE inits a new SSEReply with the status http.StatusOK and the content type text/event-stream.
+func SSE() *SSEReply {
+	header := make(http.Header)
+	header.Add(headers.ContentType, mimetypes.EventStream)
+	header.Add(headers.CacheControl, "no-cache")
+
+	return &SSEReply{status: http.StatusOK, header: header, events: make([]SSEEvent, 0)}
+}
+
+// Status sets the HTTP status code.
+func (r *SSEReply) Status(status int) *SSEReply {
+	r.status = status
+	return r
+}
+
+// Header adds a header to the response.
+func (r *SSEReply) Header(key, value string) *SSEReply {
+	r.header.Add(key, value)
+	return r
+}
+
+// Event adds an event to the sequence.
+func (r *SSEReply) Event(event SSEEvent) *SSEReply {
+	r.events = append(r.events, event)
+	return r
+}
+
+// Data adds an event, with data only, to the sequence.
+func (r *SSEReply) Data(data string) *SSEReply {
+	return r.Event(SSEEvent{Data: data})
+}
+
+// KeepOpen keeps the connection open after sending all events, until the client disconnects or
+// the mock server is closed.
+func (r *SSEReply) KeepOpen() *SSEReply {
+	r.keepOpen = true
+	return r
+}
+
+// Build builds a Response that streams the configured events.
+// Events are produced when the body is first read.
+func (r *SSEReply) Build(req *http.Request, _ M, _ params.P) (*Response, error) {
+	ctx := req.Context()
+
+	body := newLazyPipe(func(pw *io.PipeWriter) {
+		defer pw.Close()
+
+		for _, event := range r.events {
+			if event.Delay > 0 {
+				select {
+				case <-time.After(event.Delay):
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if _, err := pw.Write(event.encode()); err != nil {
+				return
+			}
+		}
+
+		if r.keepOpen {
+			<-ctx.Done()
+		}
+	})
+
+	return &Response{
+		Status:  r.status,
+		Header:  r.header.Clone(),
+		Cookies: make([]*http.Cookie, 0),
+		Body:    body,
+		Mappers: make([]ResponseMapper, 0),
+		Flush:   true,
+	}, nil
+}
+
+// encode encodes the event using the text/event-stream format.
+func (e SSEEvent) encode() []byte {
+	b := strings.Builder{}
+
+	if e.ID != "" {
+		b.WriteString(fmt.Sprintf("id: %s\n", e.ID))
+	}
+
+	if e.Event != "" {
+		b.WriteString(fmt.Sprintf("event: %s\n", e.Event))
+	}
+
+	if e.Retry > 0 {
+		b.WriteString(fmt.Sprintf("retry: %d\n", e.Retry.Milliseconds()))
+	}
+
+	for _, line := range strings.Split(e.Data, "\n") {
+		b.WriteString(fmt.Sprintf("data: %s\n", line))
+	}
+
+	b.WriteString("\n")
+
+	return []byte(b.String())
+}
@@ -0,0 +1,111 @@
+package reply
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSE(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/events", nil)
+
+	res, err := SSE().
+		Header("x-test", "ok").
+		Event(SSEEvent{ID: "1", Event: "created", Data: `{"id":1}`, Retry: 2 * time.Second}).
+		Data("line 1\nline 2").
+		Event(SSEEvent{Data: "delayed", Delay: 50 * time.Millisecond}).
+		Build(req, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.Status)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", res.Header.Get("Cache-Control"))
+	assert.Equal(t, "ok", res.Header.Get("x-test"))
+
+	start := time.Now()
+	b, err := io.ReadAll(res.Body)
+
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t,
+		"id: 1\nevent: created\nretry: 2000\ndata: {\"id\":1}\n\n"+
+			"data: line 1\ndata: line 2\n\n"+
+			"data: delayed\n\n",
+		string(b))
+}
+
+func TestSSE_KeepOpen(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost:8080/events", nil)
+
+	res, err := SSE().Data("hello").KeepOpen().Build(req, nil, nil)
+	assert.Nil(t, err)
+
+	done := make(chan []byte)
+	go func() {
+		b, _ := io.ReadAll(res.Body)
+		done <- b
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("stream should be kept open")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+
+	select {
+	case b := <-done:
+		assert.Equal(t, "data: hello\n\n", string(b))
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream should be closed when the request context is done")
+	}
+}
+
+func TestSSE_ClosedBeforeRead(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/events", nil)
+
+	res, err := SSE().Data("hello").Build(req, nil, nil)
+	assert.Nil(t, err)
+
+	closer, ok := res.Body.(io.Closer)
+	assert.True(t, ok)
+	assert.Nil(t, closer.Close())
+
+	_, err = res.Body.Read(make([]byte, 8))
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+}
+
+func TestLazyPipe(t *testing.T) {
+	started := make(chan struct{}, 1)
+	p := newLazyPipe(func(w *io.PipeWriter) {
+		started <- struct{}{}
+		w.Write([]byte("hello"))
+		w.Close()
+	})
+
+	select {
+	case <-started:
+		t.Fatal("producer should not run before the first read")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	b, err := io.ReadAll(p)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", string(b))
+	assert.Len(t, started, 1)
+
+	p = newLazyPipe(func(w *io.PipeWriter) { started <- struct{}{} })
+
+	assert.Nil(t, p.Close())
+
+	_, err = p.Read(make([]byte, 8))
+
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+}
@@ -0,0 +1,157 @@
+package reply
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/params"
+)
+
+// DefaultIndexFile is the file served by StaticReply for directory requests.
+const DefaultIndexFile = "index.html"
+
+// StaticReply serves files from a fs.FS, like os.DirFS or embed.FS, similar to an asset server or a CDN.
+// The file is resolved from the request URL path, without the configured prefix.
+// Content types come from the file extensions, ETag headers are computed from the file contents and
+// Last-Modified headers come from the file modification times, when available.
+// Range, If-None-Match, If-Match, If-Modified-Since and If-Unmodified-Since request headers are handled using
+// http.ServeContent.
+// Use Static to init a new StaticReply.
+type StaticReply struct {
+	fsys   fs.FS
+	prefix string
+	index  string
+	header http.Header
+	delay  time.Duration
+}
+
+// Static inits a new StaticReply that serves the files from the given fs.FS.
+func Static(fsys fs.FS) *StaticReply {
+	return &StaticReply{fsys: fsys, index: DefaultIndexFile, header: make(http.Header)}
+}
+
+// Prefix sets the URL path prefix removed from the request path before resolving the file.
+// E.g.: with the prefix "/assets", the request "/assets/js/app.js" serves the file "js/app.js".
+func (s *StaticReply) Prefix(prefix string) *StaticReply {
+	s.prefix = prefix
+	return s
+}
+
+// Index sets the file served for directory requests. Defaults to DefaultIndexFile.
+func (s *StaticReply) Index(name string) *StaticReply {
+	s.index = name
+	return s
+}
+
+// Header adds a header to all responses, like Cache-Control.
+func (s *StaticReply) Header(key, value string) *StaticReply {
+	s.header.Add(key, value)
+	return s
+}
+
+// Delay sets a delay time before serving the stub Response.
+func (s *StaticReply) Delay(duration time.Duration) *StaticReply {
+	s.delay = duration
+	return s
+}
+
+// Build builds a Response with the content of the file requested.
+// Missing files and directories without an index file are served with http.StatusNotFound.
+func (s *StaticReply) Build(r *http.Request, _ M, _ params.P) (*Response, error) {
+	name := s.filename(r.URL.Path)
+
+	info, err := fs.Stat(s.fsys, name)
+	if err == nil && info.IsDir() {
+		name = path.Join(name, s.index)
+		info, err = fs.Stat(s.fsys, name)
+	}
+
+	if err == nil && info.IsDir() {
+		err = fs.ErrNotExist
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			return s.status(http.StatusNotFound), nil
+		case errors.Is(err, fs.ErrPermission):
+			return s.status(http.StatusForbidden), nil
+		}
+
+		return nil, err
+	}
+
+	b, err := fs.ReadFile(s.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &bufferedResponseWriter{header: s.header.Clone()}
+	w.header.Set(headers.ETag, fmt.Sprintf(`"%x"`, sha256.Sum256(b)))
+
+	http.ServeContent(w, r, info.Name(), info.ModTime(), bytes.NewReader(b))
+	w.WriteHeader(http.StatusOK)
+
+	return &Response{
+		Status:  w.status,
+		Header:  w.header,
+		Cookies: make([]*http.Cookie, 0),
+		Body:    &w.body,
+		Delay:   s.delay,
+		Mappers: make([]ResponseMapper, 0),
+	}, nil
+}
+
+// filename returns the file name, relative to the fs.FS root, for the given URL path.
+func (s *StaticReply) filename(urlPath string) string {
+	if len(urlPath) >= len(s.prefix) && strings.EqualFold(s.prefix, urlPath[:len(s.prefix)]) {
+		urlPath = urlPath[len(s.prefix):]
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+urlPath), "/")
+	if name == "" {
+		return "."
+	}
+
+	return name
+}
+
+func (s *StaticReply) status(status int) *Response {
+	return &Response{
+		Status:  status,
+		Header:  s.header.Clone(),
+		Cookies: make([]*http.Cookie, 0),
+		Delay:   s.delay,
+		Mappers: make([]ResponseMapper, 0),
+	}
+}
+
+// bufferedResponseWriter is a http.ResponseWriter that keeps the response in memory.
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
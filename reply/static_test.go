@@ -0,0 +1,125 @@
+package reply
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+)
+
+func TestStaticReply(t *testing.T) {
+	modTime := time.Date(2022, time.October, 10, 10, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"index.html":     {Data: []byte("<h1>home</h1>"), ModTime: modTime},
+		"js/app.js":      {Data: []byte("console.log('hello world')"), ModTime: modTime},
+		"docs/readme.md": {Data: []byte("docs"), ModTime: modTime},
+	}
+
+	rpl := Static(fsys).Prefix("/assets").Header(headers.CacheControl, "max-age=60")
+
+	build := func(t *testing.T, target string, header map[string]string) (*Response, string) {
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080"+target, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+
+		res, err := rpl.Build(req, _testMock, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Body == nil {
+			return res, ""
+		}
+
+		b, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return res, string(b)
+	}
+
+	t.Run("should serve the file with its content type", func(t *testing.T) {
+		res, body := build(t, "/assets/js/app.js", nil)
+
+		assert.Equal(t, http.StatusOK, res.Status)
+		assert.Contains(t, res.Header.Get(headers.ContentType), "javascript")
+		assert.Equal(t, "max-age=60", res.Header.Get(headers.CacheControl))
+		assert.Equal(t, modTime.Format(http.TimeFormat), res.Header.Get(headers.LastModified))
+		assert.NotEmpty(t, res.Header.Get(headers.ETag))
+		assert.Equal(t, "console.log('hello world')", body)
+	})
+
+	t.Run("should serve the index file for directories", func(t *testing.T) {
+		for _, target := range []string{"/assets", "/assets/", "/assets/../"} {
+			res, body := build(t, target, nil)
+
+			assert.Equal(t, http.StatusOK, res.Status)
+			assert.Equal(t, "text/html; charset=utf-8", res.Header.Get(headers.ContentType))
+			assert.Equal(t, "<h1>home</h1>", body)
+		}
+	})
+
+	t.Run("should serve not found for missing files and directories without index", func(t *testing.T) {
+		for _, target := range []string{"/assets/js/none.js", "/assets/docs", "/assets/../../etc/passwd"} {
+			res, _ := build(t, target, nil)
+
+			assert.Equal(t, http.StatusNotFound, res.Status)
+			assert.Equal(t, "max-age=60", res.Header.Get(headers.CacheControl))
+		}
+	})
+
+	t.Run("should serve ranges", func(t *testing.T) {
+		res, body := build(t, "/assets/js/app.js", map[string]string{headers.Range: "bytes=0-6"})
+
+		assert.Equal(t, http.StatusPartialContent, res.Status)
+		assert.Equal(t, "bytes 0-6/26", res.Header.Get(headers.ContentRange))
+		assert.Equal(t, "console", body)
+	})
+
+	t.Run("should serve not modified when etag matches", func(t *testing.T) {
+		res, _ := build(t, "/assets/js/app.js", nil)
+		etag := res.Header.Get(headers.ETag)
+
+		res, body := build(t, "/assets/js/app.js", map[string]string{headers.IfNoneMatch: etag})
+
+		assert.Equal(t, http.StatusNotModified, res.Status)
+		assert.Empty(t, body)
+
+		res, _ = build(t, "/assets/js/app.js", map[string]string{headers.IfNoneMatch: `"other"`})
+
+		assert.Equal(t, http.StatusOK, res.Status)
+	})
+
+	t.Run("should serve not modified when file was not modified since", func(t *testing.T) {
+		res, _ := build(t, "/assets/js/app.js",
+			map[string]string{headers.IfModifiedSince: modTime.Add(time.Hour).Format(http.TimeFormat)})
+
+		assert.Equal(t, http.StatusNotModified, res.Status)
+
+		res, _ = build(t, "/assets/js/app.js",
+			map[string]string{headers.IfModifiedSince: modTime.Add(-time.Hour).Format(http.TimeFormat)})
+
+		assert.Equal(t, http.StatusOK, res.Status)
+	})
+
+	t.Run("should use the configured index file", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/docs/", nil)
+		res, err := Static(fsys).Index("readme.md").Build(req, _testMock, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := io.ReadAll(res.Body)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, res.Status)
+		assert.Equal(t, "docs", string(b))
+	})
+}
@@ -0,0 +1,99 @@
+package reply
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/vitorsalgado/mocha/v3/params"
+)
+
+type (
+	// StreamReply represents a response stub that writes its body as a sequence of chunks,
+	// waiting for the configured delay before each one. Every chunk is flushed to the client as soon as it is written.
+	// Use Stream to init a new StreamReply.
+	StreamReply struct {
+		status  int
+		header  http.Header
+		trailer http.Header
+		chunks  []streamChunk
+	}
+
+	streamChunk struct {
+		data  []byte
+		delay time.Duration
+	}
+)
+
+// Stream inits a new StreamReply with the status http.StatusOK.
+func Stream() *StreamReply {
+	return &StreamReply{
+		status:  http.StatusOK,
+		header:  make(http.Header),
+		trailer: make(http.Header),
+		chunks:  make([]streamChunk, 0),
+	}
+}
+
+// Status sets the HTTP status code.
+func (r *StreamReply) Status(status int) *StreamReply {
+	r.status = status
+	return r
+}
+
+// Header adds a header to the response.
+func (r *StreamReply) Header(key, value string) *StreamReply {
+	r.header.Add(key, value)
+	return r
+}
+
+// Trailer adds a trailer, sent after the last chunk.
+func (r *StreamReply) Trailer(key, value string) *StreamReply {
+	r.trailer.Add(key, value)
+	return r
+}
+
+// Chunk adds a chunk to the body. It is written after waiting for the given delay.
+func (r *StreamReply) Chunk(data []byte, delay time.Duration) *StreamReply {
+	r.chunks = append(r.chunks, streamChunk{data: data, delay: delay})
+	return r
+}
+
+// ChunkString adds a string chunk to the body. It is written after waiting for the given delay.
+func (r *StreamReply) ChunkString(data string, delay time.Duration) *StreamReply {
+	return r.Chunk([]byte(data), delay)
+}
+
+// Build builds a Response that writes the configured chunks.
+// Chunks are produced when the body is first read, and writing stops if the client disconnects.
+func (r *StreamReply) Build(req *http.Request, _ M, _ params.P) (*Response, error) {
+	ctx := req.Context()
+
+	body := newLazyPipe(func(pw *io.PipeWriter) {
+		defer pw.Close()
+
+		for _, chunk := range r.chunks {
+			if chunk.delay > 0 {
+				select {
+				case <-time.After(chunk.delay):
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if _, err := pw.Write(chunk.data); err != nil {
+				return
+			}
+		}
+	})
+
+	return &Response{
+		Status:  r.status,
+		Header:  r.header.Clone(),
+		Cookies: make([]*http.Cookie, 0),
+		Body:    body,
+		Trailer: r.trailer.Clone(),
+		Mappers: make([]ResponseMapper, 0),
+		Flush:   true,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package reply
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStream(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/stream", nil)
+
+	res, err := Stream().
+		Status(http.StatusAccepted).
+		Header("content-type", "text/plain").
+		Trailer("x-checksum", "abc").
+		ChunkString("hello ", 0).
+		Chunk([]byte("world"), 50*time.Millisecond).
+		Build(req, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusAccepted, res.Status)
+	assert.Equal(t, "text/plain", res.Header.Get("content-type"))
+	assert.Equal(t, "abc", res.Trailer.Get("x-checksum"))
+
+	start := time.Now()
+	b, err := io.ReadAll(res.Body)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "hello world", string(b))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestStream_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost:8080/stream", nil)
+
+	res, err := Stream().
+		ChunkString("first", 0).
+		ChunkString("second", time.Minute).
+		Build(req, nil, nil)
+	assert.Nil(t, err)
+
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(res.Body, buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "first", string(buf))
+
+	cancel()
+
+	b, err := io.ReadAll(res.Body)
+	assert.Nil(t, err)
+	assert.Len(t, b, 0)
+}
+
+func TestStream_ClosedBeforeRead(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/stream", nil)
+
+	res, err := Stream().ChunkString("hello", 0).Build(req, nil, nil)
+	assert.Nil(t, err)
+
+	closer, ok := res.Body.(io.Closer)
+	assert.True(t, ok)
+	assert.Nil(t, closer.Close())
+
+	_, err = res.Body.Read(make([]byte, 8))
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+}
@@ -3,7 +3,10 @@ package reply
 import (
 	"io"
 	"net/http"
+	"strings"
 	"text/template"
+
+	"github.com/vitorsalgado/mocha/v3/params"
 )
 
 type (
@@ -24,6 +27,33 @@ type (
 		// Data is the model to be used with the given template.
 		// This value is set using the Model() function from StdReply.
 		Data any
+
+		// Body is the parsed request body. JSON bodies are available as maps and lists,
+		// so fields can be accessed like {{ .Body.user.name }}.
+		Body any
+
+		// RawBody is the request body content.
+		RawBody string
+
+		// Path holds the request URL path segments. E.g.: {{ index .Path 1 }} returns "10" for "/users/10".
+		Path []string
+
+		// Params holds all the mock server parameters.
+		Params map[string]any
+
+		// Scenario is the scenario of the matched mock.
+		Scenario TemplateScenario
+
+		mock M
+	}
+
+	// TemplateScenario describes a scenario in the template data model.
+	TemplateScenario struct {
+		// Name is the scenario name. It is empty when the mock has no scenario.
+		Name string
+
+		// State is the scenario state after the current request.
+		State string
 	}
 
 	// TextTemplate is the built-in text Template interface.
@@ -36,6 +66,79 @@ type (
 	}
 )
 
+// newTemplateData builds the TemplateData for the given request.
+func newTemplateData(r *http.Request, m M, p params.P, model any) *TemplateData {
+	values := RequestValuesFromRequest(r)
+	data := &TemplateData{
+		Request:  r,
+		Data:     model,
+		Body:     values.ParsedBody,
+		RawBody:  string(values.RawBody),
+		Path:     make([]string, 0),
+		Params:   make(map[string]any),
+		Scenario: TemplateScenario{Name: values.ScenarioName, State: values.ScenarioState},
+		mock:     m,
+	}
+
+	if r != nil {
+		if path := strings.Trim(r.URL.Path, "/"); path != "" {
+			data.Path = strings.Split(path, "/")
+		}
+	}
+
+	if p != nil {
+		data.Params = p.GetAll()
+	}
+
+	return data
+}
+
+// Query returns the first value of the given request query parameter.
+func (td *TemplateData) Query(key string) string {
+	if td.Request == nil {
+		return ""
+	}
+
+	return td.Request.URL.Query().Get(key)
+}
+
+// Header returns the first value of the given request header.
+func (td *TemplateData) Header(key string) string {
+	if td.Request == nil {
+		return ""
+	}
+
+	return td.Request.Header.Get(key)
+}
+
+// Cookie returns the value of the given request cookie.
+func (td *TemplateData) Cookie(name string) string {
+	if td.Request == nil {
+		return ""
+	}
+
+	cookie, err := td.Request.Cookie(name)
+	if err != nil {
+		return ""
+	}
+
+	return cookie.Value
+}
+
+// Hits returns the number of times the mock was called, including the current request.
+func (td *TemplateData) Hits() int {
+	if td.mock == nil {
+		return 0
+	}
+
+	return td.mock.Hits() + 1
+}
+
+// Param returns the mock server parameter with the given key.
+func (td *TemplateData) Param(key string) any {
+	return td.Params[key]
+}
+
 // NewTextTemplate creates a new BuiltInTemplate.
 func NewTextTemplate() *TextTemplate {
 	return &TextTemplate{funcMap: make(template.FuncMap)}
@@ -48,6 +151,7 @@ func (gt *TextTemplate) Name(name string) *TextTemplate {
 }
 
 // FuncMap adds a new function to be used inside the Go template.
+// Functions with the same name of built-in ones take precedence. See TemplateFuncs for the built-in functions.
 func (gt *TextTemplate) FuncMap(fn template.FuncMap) *TextTemplate {
 	gt.funcMap = fn
 	return gt
@@ -60,7 +164,7 @@ func (gt *TextTemplate) Template(tmpl string) *TextTemplate {
 }
 
 func (gt *TextTemplate) Compile() error {
-	t, err := template.New(gt.name).Funcs(gt.funcMap).Parse(gt.template)
+	t, err := template.New(gt.name).Funcs(TemplateFuncs()).Funcs(gt.funcMap).Parse(gt.template)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,201 @@
+package reply
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/vitorsalgado/mocha/v3/internal/jsonx"
+)
+
+const _alphanumeric = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// timeLayouts maps the names of the standard time layouts, accepted by the formatTime template function.
+var timeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"RFC822":      time.RFC822,
+	"RFC1123":     time.RFC1123,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"DateTime":    "2006-01-02 15:04:05",
+	"DateOnly":    "2006-01-02",
+	"TimeOnly":    "15:04:05",
+}
+
+// TemplateFuncs returns the functions available to all TextTemplate instances:
+//
+//	uuid                      random UUID v4
+//	now                       current time.Time
+//	formatTime layout t       formats the time using a Go layout or a standard layout name, like RFC3339
+//	unix t                    Unix time in seconds
+//	randInt min max           random integer in the interval [min, max)
+//	randString n              random alphanumeric string with n characters
+//	jsonPath path v           value in the JSON path, like {{ jsonPath "items[0].id" .Body }}
+//	toJSON v                  JSON encoded value
+//	base64Encode s            standard base64 encoded string
+//	base64Decode s            standard base64 decoded string
+//	add, sub, mul, div, mod   math operations. Results are float64, except for mod
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"uuid":         uuid,
+		"now":          time.Now,
+		"formatTime":   formatTime,
+		"unix":         func(t time.Time) int64 { return t.Unix() },
+		"randInt":      randInt,
+		"randString":   randString,
+		"jsonPath":     jsonPath,
+		"toJSON":       toJSON,
+		"base64Encode": func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"base64Decode": base64Decode,
+		"add":          mathOp(func(a, b float64) (float64, error) { return a + b, nil }),
+		"sub":          mathOp(func(a, b float64) (float64, error) { return a - b, nil }),
+		"mul":          mathOp(func(a, b float64) (float64, error) { return a * b, nil }),
+		"div": mathOp(func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, errors.New("division by zero")
+			}
+
+			return a / b, nil
+		}),
+		"mod": mod,
+	}
+}
+
+func uuid() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+func formatTime(layout string, t time.Time) string {
+	if l, ok := timeLayouts[layout]; ok {
+		layout = l
+	}
+
+	return t.Format(layout)
+}
+
+func randInt(lower, upper int) (int, error) {
+	if upper <= lower {
+		return 0, fmt.Errorf("randInt max %d must be greater than min %d", upper, lower)
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(upper-lower)))
+	if err != nil {
+		return 0, err
+	}
+
+	return lower + int(n.Int64()), nil
+}
+
+func randString(n int) (string, error) {
+	b := make([]byte, n)
+	size := big.NewInt(int64(len(_alphanumeric)))
+
+	for i := range b {
+		idx, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return "", err
+		}
+
+		b[i] = _alphanumeric[idx.Int64()]
+	}
+
+	return string(b), nil
+}
+
+// jsonPath returns the value in the given path or nil when it doesn't exist.
+func jsonPath(path string, v any) (any, error) {
+	value, err := jsonx.Reach(path, v)
+	if errors.Is(err, jsonx.ErrFieldNotFound) {
+		return nil, nil
+	}
+
+	return value, err
+}
+
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func base64Decode(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	return string(b), err
+}
+
+func mathOp(op func(a, b float64) (float64, error)) func(a, b any) (float64, error) {
+	return func(a, b any) (float64, error) {
+		x, err := toFloat(a)
+		if err != nil {
+			return 0, err
+		}
+
+		y, err := toFloat(b)
+		if err != nil {
+			return 0, err
+		}
+
+		return op(x, y)
+	}
+}
+
+func mod(a, b any) (int64, error) {
+	x, err := toFloat(a)
+	if err != nil {
+		return 0, err
+	}
+
+	y, err := toFloat(b)
+	if err != nil {
+		return 0, err
+	}
+
+	if int64(y) == 0 {
+		return 0, errors.New("division by zero")
+	}
+
+	return int64(x) % int64(y), nil
+}
+
+func toFloat(v any) (float64, error) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) {
+			return 0, errors.New("value is not a number")
+		}
+
+		return f, nil
+	case reflect.String:
+		f, err := strconv.ParseFloat(rv.String(), 64)
+		if err != nil {
+			return 0, fmt.Errorf("value %q is not a number", rv.String())
+		}
+
+		return f, nil
+	}
+
+	return 0, fmt.Errorf("value %v is not a number", v)
+}
@@ -9,8 +9,11 @@ import (
 	"strings"
 	"testing"
 	"text/template"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3/params"
 )
 
 type testData struct {
@@ -84,3 +87,174 @@ func TestReplyWithTemplate(t *testing.T) {
 	assert.Equal(t, http.StatusOK, res.Status)
 	assert.Equal(t, "test\ndev\n", string(b))
 }
+
+func TestReplyWithTemplate_RequestData(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/users/10/orders?sort=desc", nil)
+	req.Header.Add("x-correlation-id", "abc")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+	req = req.WithContext(ContextWithRequestValues(req.Context(), &RequestValues{
+		ParsedBody:    map[string]any{"name": "dev", "items": []any{map[string]any{"id": 1.0}}},
+		RawBody:       []byte(`{"name": "dev"}`),
+		ScenarioName:  "checkout",
+		ScenarioState: "paid",
+	}))
+
+	m := &mmock{}
+	m.On("Hits").Return(2)
+
+	p := params.New()
+	p.Set("env", "test")
+
+	res, err := OK().
+		BodyTemplate(`{{ .Body.name }} {{ jsonPath "items[0].id" .Body }} {{ .RawBody }}
+{{ index .Path 1 }} {{ len .Path }} {{ .Query "sort" }} {{ .Header "x-correlation-id" }} {{ .Cookie "session" }}
+{{ .Param "env" }} {{ .Params.env }} {{ .Hits }} {{ .Scenario.Name }} {{ .Scenario.State }}`).
+		Build(req, m, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := io.ReadAll(res.Body)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "dev 1 {\"name\": \"dev\"}\n10 3 desc abc s1\ntest test 3 checkout paid", string(b))
+}
+
+func TestReplyWithTemplate_StatusHeadersAndCookies(t *testing.T) {
+	newRequest := func(id any) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/users", nil)
+		req.Header.Add("X-Correlation-ID", "abc")
+
+		return req.WithContext(ContextWithRequestValues(req.Context(), &RequestValues{
+			ParsedBody: map[string]any{"id": id},
+		}))
+	}
+
+	rpl := New().
+		StatusTemplate(`{{ if .Body.id }}201{{ else }}400{{ end }}`).
+		Header("x-static", "ok").
+		HeaderTemplate("X-Correlation-ID", `{{ .Header "X-Correlation-ID" }}`).
+		HeaderTemplate("Location", NewTextTemplate().Template(`/users/{{ .Body.id }}`)).
+		Cookie(http.Cookie{Name: "static", Value: "ok"}).
+		CookieTemplate(http.Cookie{Name: "user", Path: "/"}, `{{ .Body.id }}`)
+
+	res, err := rpl.Build(newRequest("10"), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusCreated, res.Status)
+	assert.Equal(t, "ok", res.Header.Get("x-static"))
+	assert.Equal(t, "abc", res.Header.Get("X-Correlation-ID"))
+	assert.Equal(t, "/users/10", res.Header.Get("Location"))
+	assert.Len(t, res.Cookies, 2)
+	assert.Equal(t, "ok", res.Cookies[0].Value)
+	assert.Equal(t, "user", res.Cookies[1].Name)
+	assert.Equal(t, "/", res.Cookies[1].Path)
+	assert.Equal(t, "10", res.Cookies[1].Value)
+
+	res, err = rpl.Build(newRequest(nil), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, res.Status)
+	assert.Equal(t, []string{"/users/<no value>"}, res.Header.Values("Location"))
+	assert.Len(t, res.Cookies, 2)
+
+	t.Run("should return error when status template does not render a status code", func(t *testing.T) {
+		_, err := New().StatusTemplate(`{{ .Body.id }}`).Build(newRequest("abc"), nil, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("should return compile errors on build", func(t *testing.T) {
+		_, err := OK().HeaderTemplate("x-test", "{{ .Body.id }").Build(newRequest("10"), nil, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("should panic when template type is not supported", func(t *testing.T) {
+		assert.Panics(t, func() { OK().HeaderTemplate("x-test", 10) })
+	})
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	render := func(tmpl string, data any) (string, error) {
+		tt := NewTextTemplate().Template(tmpl)
+		if err := tt.Compile(); err != nil {
+			return "", err
+		}
+
+		buf := &bytes.Buffer{}
+		err := tt.Parse(buf, data)
+
+		return buf.String(), err
+	}
+
+	testCases := []struct {
+		tmpl     string
+		data     any
+		expected string
+	}{
+		{`{{ formatTime "DateOnly" . }}`, time.Date(2022, 10, 5, 0, 0, 0, 0, time.UTC), "2022-10-05"},
+		{`{{ formatTime "02/01/2006" . }}`, time.Date(2022, 10, 5, 0, 0, 0, 0, time.UTC), "05/10/2022"},
+		{`{{ unix . }}`, time.Unix(100, 0), "100"},
+		{`{{ jsonPath "a.b" . }}`, map[string]any{"a": map[string]any{"b": "c"}}, "c"},
+		{`{{ jsonPath "a[*]" . }}`, map[string]any{"a": []any{1, 2}}, "[1 2]"},
+		{`{{ if jsonPath "x" . }}yes{{ else }}no{{ end }}`, map[string]any{}, "no"},
+		{`{{ toJSON . }}`, map[string]any{"a": 1}, `{"a":1}`},
+		{`{{ base64Encode "hello" }}`, nil, "aGVsbG8="},
+		{`{{ base64Decode "aGVsbG8=" }}`, nil, "hello"},
+		{`{{ add 1 .n }}`, map[string]any{"n": 2.5}, "3.5"},
+		{`{{ sub .n 1 }}`, map[string]any{"n": "10"}, "9"},
+		{`{{ mul 2 3 }}`, nil, "6"},
+		{`{{ div 9 2 }}`, nil, "4.5"},
+		{`{{ mod 9 2 }}`, nil, "1"},
+		{`{{ len (randString 12) }}`, nil, "12"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.tmpl, func(t *testing.T) {
+			res, err := render(tc.tmpl, tc.data)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+
+	t.Run("uuid", func(t *testing.T) {
+		res, err := render(`{{ uuid }}`, nil)
+		assert.Nil(t, err)
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, res)
+	})
+
+	t.Run("randInt", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			res, err := render(`{{ randInt 5 8 }}`, nil)
+			assert.Nil(t, err)
+			assert.Contains(t, []string{"5", "6", "7"}, res)
+		}
+	})
+
+	t.Run("now", func(t *testing.T) {
+		res, err := render(`{{ formatTime "2006" now }}`, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, time.Now().Format("2006"), res)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tmpl := range []string{`{{ div 1 0 }}`, `{{ mod 1 0 }}`, `{{ add "a" 1 }}`, `{{ randInt 2 1 }}`, `{{ base64Decode "?" }}`} {
+			_, err := render(tmpl, nil)
+			assert.NotNil(t, err, tmpl)
+		}
+	})
+
+	t.Run("user functions take precedence", func(t *testing.T) {
+		tt := NewTextTemplate().
+			FuncMap(template.FuncMap{"uuid": func() string { return "fixed" }}).
+			Template(`{{ uuid }}`)
+		assert.Nil(t, tt.Compile())
+
+		buf := &bytes.Buffer{}
+		assert.Nil(t, tt.Parse(buf, nil))
+		assert.Equal(t, "fixed", buf.String())
+	})
+}
@@ -0,0 +1,256 @@
+package reply
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/params"
+)
+
+// WebSocket message types.
+const (
+	WebSocketTextMessage   = websocket.TextMessage
+	WebSocketBinaryMessage = websocket.BinaryMessage
+)
+
+// _webSocketCloseTimeout is the time to wait for the client to acknowledge a close message.
+const _webSocketCloseTimeout = time.Second
+
+type (
+	// WebSocketMessage is a WebSocket data message.
+	WebSocketMessage struct {
+		// Type is the message type: WebSocketTextMessage or WebSocketBinaryMessage.
+		Type int
+
+		// Data is the message payload.
+		Data []byte
+	}
+
+	// WebSocketReceivedMessage is a WebSocket data message received from a client.
+	WebSocketReceivedMessage struct {
+		WebSocketMessage
+
+		// Request is the upgrade request of the connection that received the message.
+		Request *http.Request
+	}
+
+	// WebSocketReply serves a scripted WebSocket conversation.
+	// Steps run in the order they were added. After the last step, unless the connection was closed by a Close step,
+	// incoming messages are received until the client disconnects or the mock server is closed.
+	// Every received message is recorded. Use WebSocket to init a new WebSocketReply.
+	WebSocketReply struct {
+		steps    []webSocketStep
+		upgrader websocket.Upgrader
+		received []WebSocketReceivedMessage
+		mu       sync.Mutex
+	}
+
+	webSocketStep struct {
+		send    []WebSocketMessage
+		matcher *expect.Matcher
+		json    bool
+		close   bool
+		code    int
+		reason  string
+	}
+)
+
+// WebSocketText creates a text WebSocketMessage.
+func WebSocketText(text string) WebSocketMessage {
+	return WebSocketMessage{Type: WebSocketTextMessage, Data: []byte(text)}
+}
+
+// WebSocketBinary creates a binary WebSocketMessage.
+func WebSocketBinary(data []byte) WebSocketMessage {
+	return WebSocketMessage{Type: WebSocketBinaryMessage, Data: data}
+}
+
+// WebSocketJSON creates a text WebSocketMessage with the JSON encoding of the given value.
+// It panics if the value cannot be encoded.
+func WebSocketJSON(v any) WebSocketMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return WebSocketMessage{Type: WebSocketTextMessage, Data: b}
+}
+
+// WebSocket inits a new WebSocketReply.
+func WebSocket() *WebSocketReply {
+	return &WebSocketReply{
+		steps:    make([]webSocketStep, 0),
+		upgrader: websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }},
+		received: make([]WebSocketReceivedMessage, 0),
+	}
+}
+
+// Send adds a step that sends the given messages to the client.
+// Added first, it sends messages as soon as the client connects.
+func (r *WebSocketReply) Send(messages ...WebSocketMessage) *WebSocketReply {
+	r.steps = append(r.steps, webSocketStep{send: messages})
+	return r
+}
+
+// Expect adds a step that waits for the next client message and matches it with the given matcher, replying
+// with the given messages. Text messages are passed to the matcher as string and binary ones as []byte.
+// If the message doesn't match, the connection is closed with the status policy violation.
+func (r *WebSocketReply) Expect(matcher expect.Matcher, replies ...WebSocketMessage) *WebSocketReply {
+	r.steps = append(r.steps, webSocketStep{send: replies, matcher: &matcher})
+	return r
+}
+
+// ExpectJSON works like Expect, but it passes the message decoded from JSON to the matcher.
+func (r *WebSocketReply) ExpectJSON(matcher expect.Matcher, replies ...WebSocketMessage) *WebSocketReply {
+	r.steps = append(r.steps, webSocketStep{send: replies, matcher: &matcher, json: true})
+	return r
+}
+
+// Close adds a step that closes the connection with the given status code and reason.
+func (r *WebSocketReply) Close(code int, reason string) *WebSocketReply {
+	r.steps = append(r.steps, webSocketStep{close: true, code: code, reason: reason})
+	return r
+}
+
+// Received returns all messages received from clients, along with the upgrade requests of their connections.
+func (r *WebSocketReply) Received() []WebSocketReceivedMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	received := make([]WebSocketReceivedMessage, len(r.received))
+	copy(received, r.received)
+
+	return received
+}
+
+// Build always fails, as WebSocket conversations are served by Upgrade.
+func (r *WebSocketReply) Build(_ *http.Request, _ M, _ params.P) (*Response, error) {
+	return nil, errors.New("websocket replies must be served by upgrading the connection")
+}
+
+// Upgrade upgrades the connection to the WebSocket protocol and runs the conversation steps.
+// The connection is closed when the request context is done.
+func (r *WebSocketReply) Upgrade(w http.ResponseWriter, req *http.Request, _ M, p params.P) error {
+	conn, err := r.upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-req.Context().Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	args := expect.Args{RequestInfo: &expect.RequestInfo{Request: req}, Params: p}
+
+	for i, step := range r.steps {
+		if step.close {
+			return closeWebSocket(conn, step.code, step.reason)
+		}
+
+		if step.matcher != nil {
+			msg, err := r.read(conn, req)
+			if err != nil {
+				return fmt.Errorf("websocket step %d: connection closed waiting for a message. reason=%v", i, err)
+			}
+
+			value, err := msg.value(step.json)
+			if err != nil {
+				closeWebSocket(conn, websocket.ClosePolicyViolation, "invalid message")
+				return fmt.Errorf("websocket step %d: %v", i, err)
+			}
+
+			matched, err := step.matcher.Matches(value, args)
+			if err != nil || !matched {
+				closeWebSocket(conn, websocket.ClosePolicyViolation, "unexpected message")
+
+				if err != nil {
+					return fmt.Errorf("websocket step %d: matcher %s returned an error=%v", i, step.matcher.Name, err)
+				}
+
+				desc := ""
+				if step.matcher.DescribeMismatch != nil {
+					desc = step.matcher.DescribeMismatch("websocket message", value)
+				}
+
+				return fmt.Errorf("websocket step %d: message did not match %s. %s", i, step.matcher.Name, desc)
+			}
+		}
+
+		for _, msg := range step.send {
+			if err = conn.WriteMessage(msg.Type, msg.Data); err != nil {
+				return fmt.Errorf("websocket step %d: error sending message. reason=%v", i, err)
+			}
+		}
+	}
+
+	for {
+		if _, err = r.read(conn, req); err != nil {
+			return nil
+		}
+	}
+}
+
+func (r *WebSocketReply) read(conn *websocket.Conn, req *http.Request) (WebSocketMessage, error) {
+	mt, data, err := conn.ReadMessage()
+	if err != nil {
+		return WebSocketMessage{}, err
+	}
+
+	msg := WebSocketMessage{Type: mt, Data: data}
+
+	r.mu.Lock()
+	r.received = append(r.received, WebSocketReceivedMessage{WebSocketMessage: msg, Request: req})
+	r.mu.Unlock()
+
+	return msg, nil
+}
+
+// value returns the message value to be passed to matchers.
+func (m WebSocketMessage) value(decodeJSON bool) (any, error) {
+	if decodeJSON {
+		var v any
+		if err := json.Unmarshal(m.Data, &v); err != nil {
+			return nil, fmt.Errorf("message is not a valid json. reason=%v", err)
+		}
+
+		return v, nil
+	}
+
+	if m.Type == WebSocketTextMessage {
+		return string(m.Data), nil
+	}
+
+	return m.Data, nil
+}
+
+// closeWebSocket sends a close message and waits for the client to acknowledge it.
+func closeWebSocket(conn *websocket.Conn, code int, reason string) error {
+	deadline := time.Now().Add(_webSocketCloseTimeout)
+	if err := conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline); err != nil {
+		return err
+	}
+
+	conn.SetReadDeadline(deadline)
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return nil
+		}
+	}
+}
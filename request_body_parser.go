@@ -2,13 +2,21 @@ package mocha
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"encoding/json"
+	"errors"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/vitorsalgado/mocha/v3/internal/headers"
 	"github.com/vitorsalgado/mocha/v3/internal/mimetypes"
+	"github.com/vitorsalgado/mocha/v3/internal/xmlx"
 )
 
 // RequestBodyParser parses request body if CanParse returns true.
@@ -24,31 +32,103 @@ type RequestBodyParser interface {
 
 // parseRequestBody tests given parsers until it finds one that can parse the request body.
 // User provided RequestBodyParser takes precedence.
-func parseRequestBody(r *http.Request, parsers []RequestBodyParser) (any, error) {
+// Bodies encoded with gzip or deflate are decoded first. The request body is replaced by the decoded content,
+// so parsers and replies see the same content. Request headers are kept as they were sent, so matchers, the request
+// journal and strict reports still see the Content-Encoding header.
+// Bodies with other encodings are not parsed.
+// It returns the parsed body, the raw body content and whether the body was decoded.
+func parseRequestBody(r *http.Request, parsers []RequestBodyParser) (any, []byte, bool, error) {
 	if r.Body != nil && r.Method != http.MethodGet && r.Method != http.MethodHead {
 		b, err := io.ReadAll(r.Body)
 		if err != nil {
-			return nil, err
+			return nil, nil, false, err
 		}
 
 		r.Body.Close()
 		r.Body = io.NopCloser(bytes.NewBuffer(b))
 
+		decoded := false
+
+		if encoding := r.Header.Get(headers.ContentEncoding); encoding != "" {
+			content, ok, err := decodeBody(b, encoding)
+			if err != nil {
+				return nil, b, false, err
+			} else if !ok {
+				return nil, b, false, nil
+			}
+
+			b = content
+			decoded = true
+
+			r.Body = io.NopCloser(bytes.NewBuffer(b))
+		}
+
 		contentType := r.Header.Get(headers.ContentType)
 
 		for _, parse := range parsers {
 			if parse.CanParse(contentType, r) {
 				body, err := parse.Parse(b, r)
 				if err != nil {
-					return nil, err
+					return nil, b, decoded, err
 				}
 
-				return body, nil
+				return body, b, decoded, nil
+			}
+		}
+
+		return nil, b, decoded, nil
+	}
+
+	return nil, nil, false, nil
+}
+
+// withoutContentCoding returns a copy of the request, with the decoded body, that doesn't declare a content coding.
+// It is used by replies, like proxies, that would otherwise forward the decoded body as if it was still encoded.
+func withoutContentCoding(r *http.Request, body []byte) *http.Request {
+	c := r.Clone(r.Context())
+	c.Header.Del(headers.ContentEncoding)
+	c.Header.Del(headers.ContentLength)
+	c.ContentLength = int64(len(body))
+	c.Body = io.NopCloser(bytes.NewReader(body))
+
+	return c
+}
+
+// decodeBody decodes the body according to the comma separated list of content codings, in the order they were
+// applied. It returns false when any of them is not supported.
+func decodeBody(b []byte, encoding string) ([]byte, bool, error) {
+	codings := strings.Split(encoding, ",")
+
+	for i := len(codings) - 1; i >= 0; i-- {
+		var reader io.Reader
+		var err error
+
+		switch strings.ToLower(strings.TrimSpace(codings[i])) {
+		case "identity", "":
+			continue
+		case "gzip", "x-gzip":
+			reader, err = gzip.NewReader(bytes.NewReader(b))
+		case "deflate":
+			// deflate content should be zlib wrapped, but some clients send the raw format.
+			reader, err = zlib.NewReader(bytes.NewReader(b))
+			if errors.Is(err, zlib.ErrHeader) {
+				reader, err = flate.NewReader(bytes.NewReader(b)), nil
 			}
+		default:
+			return nil, false, nil
+		}
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		b, err = io.ReadAll(reader)
+		if err != nil {
+			return nil, false, err
 		}
 	}
 
-	return nil, nil
+	return b, true, nil
 }
 
 // jsonBodyParser parses requests with content type header containing "application/json"
@@ -79,6 +159,73 @@ func (parser *formURLEncodedParser) Parse(_ []byte, r *http.Request) (any, error
 	return r.Form, nil
 }
 
+// xmlBodyParser parses requests with content type header containing "application/xml", "text/xml" or
+// the suffix "+xml", like SOAP 1.2 requests. Use expect.XPath to match the parsed body.
+type xmlBodyParser struct{}
+
+func (parser *xmlBodyParser) CanParse(content string, _ *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(content)
+
+	return mediaType == mimetypes.XML || mediaType == mimetypes.TextXML || strings.HasSuffix(mediaType, "+xml")
+}
+
+func (parser *xmlBodyParser) Parse(body []byte, _ *http.Request) (any, error) {
+	return xmlx.Parse(body)
+}
+
+// multipartFormParser parses requests with content type header containing "multipart/form-data".
+// The parsed body is a *MultipartForm.
+type multipartFormParser struct{}
+
+func (parser *multipartFormParser) CanParse(content string, _ *http.Request) bool {
+	return strings.Contains(content, mimetypes.MultipartFormData)
+}
+
+func (parser *multipartFormParser) Parse(body []byte, r *http.Request) (any, error) {
+	_, p, err := mime.ParseMediaType(r.Header.Get(headers.ContentType))
+	if err != nil {
+		return nil, err
+	}
+
+	boundary, ok := p["boundary"]
+	if !ok {
+		return nil, errors.New("multipart/form-data request without boundary")
+	}
+
+	form := &MultipartForm{Fields: make(url.Values), Files: make(map[string][]*MultipartFile)}
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return form, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+
+		name := part.FormName()
+
+		if part.FileName() == "" {
+			form.Fields.Add(name, string(content))
+			continue
+		}
+
+		form.Files[name] = append(form.Files[name], &MultipartFile{
+			Name:        name,
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get(headers.ContentType),
+			Size:        int64(len(content)),
+			Content:     content,
+			Header:      part.Header,
+		})
+	}
+}
+
 // plainTextParser parses requests with content type header containing "text/plain"
 type plainTextParser struct{}
 
@@ -1,5 +1,7 @@
 package mocha
 
+import "sync"
+
 const (
 	_scenarioStateStarted = "STARTED"
 )
@@ -21,12 +23,15 @@ func (s scenario) HasStarted() bool {
 type (
 	scenarioStore interface {
 		FetchByName(name string) (scenario, bool)
+		FetchAll() []scenario
 		CreateNewIfNeeded(name string) scenario
 		Save(s scenario)
+		Reset()
 	}
 
 	internalScenarioStore struct {
 		data map[string]scenario
+		mu   sync.RWMutex
 	}
 )
 
@@ -35,16 +40,34 @@ func newScenarioStore() scenarioStore {
 }
 
 func (store *internalScenarioStore) FetchByName(name string) (scenario, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
 	s, ok := store.data[name]
 	return s, ok
 }
 
+func (store *internalScenarioStore) FetchAll() []scenario {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	list := make([]scenario, 0, len(store.data))
+	for _, s := range store.data {
+		list = append(list, s)
+	}
+
+	return list
+}
+
 func (store *internalScenarioStore) CreateNewIfNeeded(name string) scenario {
-	s, ok := store.FetchByName(name)
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	s, ok := store.data[name]
 
 	if !ok {
 		sc := newScenario(name)
-		store.Save(sc)
+		store.data[name] = sc
 		return sc
 	}
 
@@ -52,5 +75,16 @@ func (store *internalScenarioStore) CreateNewIfNeeded(name string) scenario {
 }
 
 func (store *internalScenarioStore) Save(s scenario) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
 	store.data[s.Name] = s
 }
+
+// Reset removes all scenarios, making them start again on the next request.
+func (store *internalScenarioStore) Reset() {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.data = make(map[string]scenario)
+}
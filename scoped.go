@@ -3,6 +3,9 @@ package mocha
 import (
 	"fmt"
 	"strings"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/reply"
 )
 
 // Scoped holds references to one or more added mocks allowing users perform operations on them, like enabling/disabling.
@@ -160,3 +163,43 @@ func (s *Scoped) Hits() int {
 
 	return total
 }
+
+// WebSocketMessages returns all messages received by the scoped WebSocket mocks.
+func (s *Scoped) WebSocketMessages() []reply.WebSocketReceivedMessage {
+	messages := make([]reply.WebSocketReceivedMessage, 0)
+	for _, m := range s.mocks {
+		if ws, ok := m.Reply.(*reply.WebSocketReply); ok {
+			messages = append(messages, ws.Received()...)
+		}
+	}
+
+	return messages
+}
+
+// AssertWebSocketReceived reports an error if none of the messages received by the scoped WebSocket mocks
+// matches the given matcher. Text messages are passed to the matcher as string and binary ones as []byte.
+// Matchers receive the upgrade request of the connection that received the message.
+func (s *Scoped) AssertWebSocketReceived(t T, matcher expect.Matcher) bool {
+	t.Helper()
+
+	b := strings.Builder{}
+	messages := s.WebSocketMessages()
+
+	for _, msg := range messages {
+		var value any = msg.Data
+		if msg.Type == reply.WebSocketTextMessage {
+			value = string(msg.Data)
+		}
+
+		matched, err := matcher.Matches(value, expect.Args{RequestInfo: &expect.RequestInfo{Request: msg.Request}})
+		if err == nil && matched {
+			return true
+		}
+
+		b.WriteString(fmt.Sprintf("	message: %v\n", value))
+	}
+
+	t.Errorf("\nno websocket message matched %s.\nreceived %d messages:\n%s", matcher.Name, len(messages), b.String())
+
+	return false
+}
@@ -0,0 +1,120 @@
+package mocha
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/hooks"
+)
+
+type (
+	// Call represents a request received by a mock server other than the HTTP one, like the gRPC mock server from
+	// the package grpcmock. Use Mocha.Serve to serve it with the stored mocks.
+	Call struct {
+		// Request represents the call as an HTTP request, so it can be matched like HTTP ones.
+		Request *http.Request
+
+		// Body is the raw request body.
+		Body []byte
+
+		// ParsedBody is the request body used by body matchers.
+		ParsedBody any
+	}
+
+	// ServeFunc serves the Mock matched by Mocha.Serve.
+	// It returns the response status, which is recorded in the request journal.
+	ServeFunc func(mock *Mock) (int, error)
+
+	// NotMatchedError is returned by Mocha.Serve when the Call did not match any mock.
+	NotMatchedError struct {
+		// Result holds the result of the matching attempt.
+		Result *hooks.Result
+	}
+)
+
+func (e *NotMatchedError) Error() string {
+	builder := strings.Builder{}
+	builder.WriteString("request did not match.")
+
+	if e.Result.HasClosestMatch {
+		builder.WriteString(fmt.Sprintf(" closest match: %d %s.", e.Result.ClosestMatch.ID, e.Result.ClosestMatch.Name))
+	}
+
+	for _, detail := range e.Result.Details {
+		builder.WriteString(fmt.Sprintf(" %s, reason=%s, applied-to=%s;", detail.Name, detail.Description, detail.Target))
+	}
+
+	return builder.String()
+}
+
+// Serve serves a Call with the same mocks, scenarios, hooks and request journal used by the HTTP mock server.
+// The Call is matched against the stored mocks, considering their repeat limits and scenarios, and the matched Mock
+// is served by the given ServeFunc. Like the HTTP mock server, the Mock hit is only counted when ServeFunc succeeds.
+// Calls that don't match any mock fail with a *NotMatchedError. Errors from ServeFunc are returned as they are.
+func (m *Mocha) Serve(call *Call, serve ServeFunc) error {
+	h := m.handler
+	start := time.Now()
+	r := call.Request
+	er := hooks.FromRequest(r)
+
+	h.evt.Emit(hooks.OnRequest{Request: er, StartedAt: start})
+
+	entry := &RecordedRequest{
+		Request:    r.Clone(context.Background()),
+		Body:       call.Body,
+		ParsedBody: call.ParsedBody,
+		StartedAt:  start}
+	entry.Request.Body = http.NoBody
+
+	defer h.recordEntry(entry)
+
+	fail := func(err error) error {
+		entry.Err = err
+		h.evt.Emit(hooks.OnError{Request: er, Err: err})
+
+		return err
+	}
+
+	args := expect.Args{
+		RequestInfo: &expect.RequestInfo{Request: r, ParsedBody: call.ParsedBody},
+		Params:      h.params}
+	result, err := findMockForRequest(h.mocks, args)
+	if err != nil {
+		return fail(err)
+	}
+
+	if !result.Matches {
+		entry.Mismatch = emitNonMatched(r, result, h.evt)
+		return &NotMatchedError{Result: entry.Mismatch}
+	}
+
+	mock := result.Matched
+
+	if err = h.checkMock(mock); err != nil {
+		return fail(err)
+	}
+
+	status, err := serve(mock)
+	entry.Status = status
+
+	if err != nil {
+		return fail(err)
+	}
+
+	mock.Hit()
+
+	entry.Matched = true
+	entry.Mock = mock
+
+	h.evt.Emit(hooks.OnRequestMatch{
+		Request:            er,
+		ResponseDefinition: hooks.Response{Status: status, Header: make(http.Header)},
+		Mock:               hooks.Mock{ID: mock.ID, Name: mock.Name},
+		Elapsed:            time.Since(start)})
+
+	return nil
+}
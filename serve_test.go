@@ -0,0 +1,69 @@
+package mocha
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func TestMocha_Serve(t *testing.T) {
+	m := New(t, Configure().LogVerbosity(LogSilently).Build())
+	m.CloseOnCleanup(t)
+
+	scoped := m.AddMocks(Post(expect.URLPath("/svc/call")).
+		Body(expect.JSONPath("name", expect.ToEqual("dev"))).
+		Reply(reply.OK()))
+
+	call := func(name string) *Call {
+		r, _ := http.NewRequest(http.MethodPost, "/svc/call", nil)
+		return &Call{Request: r, Body: []byte(`{"name": "` + name + `"}`), ParsedBody: map[string]any{"name": name}}
+	}
+
+	t.Run("should serve the matched mock and record the call", func(t *testing.T) {
+		var served *Mock
+
+		err := m.Serve(call("dev"), func(mock *Mock) (int, error) {
+			served = mock
+			return 7, nil
+		})
+
+		assert.Nil(t, err)
+		assert.NotNil(t, served)
+		assert.Equal(t, 1, scoped.Hits())
+
+		requests := m.Requests()
+		assert.Len(t, requests, 1)
+		assert.True(t, requests[0].Matched)
+		assert.Equal(t, 7, requests[0].Status)
+	})
+
+	t.Run("should fail with not matched error when no mock matches", func(t *testing.T) {
+		err := m.Serve(call("qa"), func(mock *Mock) (int, error) {
+			t.Fatal("should not serve")
+			return 0, nil
+		})
+
+		var notMatched *NotMatchedError
+		assert.True(t, errors.As(err, &notMatched))
+		assert.True(t, notMatched.Result.HasClosestMatch)
+		assert.Contains(t, err.Error(), "request did not match")
+	})
+
+	t.Run("should record errors from the serve function", func(t *testing.T) {
+		m.ResetRequests()
+
+		err := m.Serve(call("dev"), func(mock *Mock) (int, error) {
+			return 13, errors.New("failed to send")
+		})
+
+		assert.EqualError(t, err, "failed to send")
+		assert.Equal(t, "failed to send", m.Requests()[0].Err.Error())
+		assert.False(t, m.Requests()[0].Matched)
+		assert.Equal(t, 1, scoped.Hits())
+	})
+}
@@ -44,6 +44,7 @@ func newServer() Server {
 func (s *httpTestServer) Configure(config Config, handler http.Handler) error {
 	s.server = httptest.NewUnstartedServer(handler)
 	s.server.EnableHTTP2 = true
+	s.server.TLS = config.TLS
 
 	if config.Addr != "" {
 		err := s.server.Listener.Close()
@@ -76,7 +77,9 @@ func (s *httpTestServer) StartTLS() (ServerInfo, error) {
 	return s.info, nil
 }
 
+// Close closes the server and all client connections, including the ones kept open by streaming replies.
 func (s *httpTestServer) Close() error {
+	s.server.CloseClientConnections()
 	s.server.Close()
 	return nil
 }
@@ -13,7 +13,11 @@ type storage interface {
 	// FetchEligible returns mocks that can be matched against requests.
 	FetchEligible() []*Mock
 
-	// FetchAll returns all stored Mock instances.
+	// ForEachEligible calls fn, in priority order, for every enabled Mock that may match the given request
+	// method and URL path. It stops when fn returns false.
+	ForEachEligible(method, path string, fn func(*Mock) bool)
+
+	// FetchAll returns a copy of the list with all stored Mock instances.
 	FetchAll() []*Mock
 
 	// Delete removes a Mock by its ID.
@@ -24,8 +28,9 @@ type storage interface {
 }
 
 type builtInStorage struct {
-	data []*Mock
-	mu   sync.Mutex
+	data  []*Mock
+	index *mockIndex
+	mu    sync.Mutex
 }
 
 // newStorage returns Mock storage implementation.
@@ -42,9 +47,14 @@ func (repo *builtInStorage) Save(mock *Mock) {
 	sort.SliceStable(repo.data, func(a, b int) bool {
 		return repo.data[a].Priority < repo.data[b].Priority
 	})
+
+	repo.index = nil
 }
 
 func (repo *builtInStorage) FetchEligible() []*Mock {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	mocks := make([]*Mock, 0)
 
 	for _, mock := range repo.data {
@@ -56,11 +66,34 @@ func (repo *builtInStorage) FetchEligible() []*Mock {
 	return mocks
 }
 
+func (repo *builtInStorage) ForEachEligible(method, path string, fn func(*Mock) bool) {
+	repo.mu.Lock()
+
+	// the index is built lazily, so loading many mocks doesn't rebuild it on every Save.
+	if repo.index == nil {
+		repo.index = newMockIndex(repo.data)
+	}
+
+	index := repo.index
+	repo.mu.Unlock()
+
+	index.each(method, path, fn)
+}
+
 func (repo *builtInStorage) FetchAll() []*Mock {
-	return repo.data
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	mocks := make([]*Mock, len(repo.data))
+	copy(mocks, repo.data)
+
+	return mocks
 }
 
 func (repo *builtInStorage) Delete(id int) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	index := -1
 	for i, m := range repo.data {
 		if m.ID == id {
@@ -69,10 +102,19 @@ func (repo *builtInStorage) Delete(id int) {
 		}
 	}
 
+	if index == -1 {
+		return
+	}
+
 	repo.data = repo.data[:index+copy(repo.data[index:], repo.data[index+1:])]
+	repo.index = nil
 }
 
 func (repo *builtInStorage) Flush() {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	repo.data = nil
 	repo.data = make([]*Mock, 0)
+	repo.index = nil
 }
@@ -0,0 +1,118 @@
+package mocha
+
+import (
+	"strings"
+)
+
+const (
+	_targetMethod = "method"
+	_targetURL    = "url"
+)
+
+type (
+	// mockIndex narrows down the mocks that may match a request using its method and URL path.
+	// Keys are derived from the expect.Literal of method and URL expectations, when available.
+	// Mocks without literals are always candidates.
+	// The index only filters candidates out. Every expectation of a candidate still runs.
+	// A mockIndex is immutable. Storage changes build a new one.
+	mockIndex struct {
+		exact    map[string][]indexEntry
+		prefixed []indexEntry
+		others   []indexEntry
+	}
+
+	// indexEntry holds a Mock along with its position in the storage and its index keys.
+	indexEntry struct {
+		mock   *Mock
+		pos    int
+		method string
+		prefix string
+	}
+)
+
+// newMockIndex builds a mockIndex for the given mocks, that must be sorted by priority.
+func newMockIndex(mocks []*Mock) *mockIndex {
+	idx := &mockIndex{
+		exact:    make(map[string][]indexEntry),
+		prefixed: make([]indexEntry, 0),
+		others:   make([]indexEntry, 0),
+	}
+
+	for i, mock := range mocks {
+		entry := indexEntry{mock: mock, pos: i}
+
+		var path string
+		var prefixed bool
+
+		for _, e := range mock.Expectations {
+			if e.Matcher.Literal == nil {
+				continue
+			}
+
+			switch e.Target {
+			case _targetMethod:
+				if !e.Matcher.Literal.Prefix && entry.method == "" {
+					entry.method = strings.ToUpper(e.Matcher.Literal.Value)
+				}
+			case _targetURL:
+				if path == "" {
+					path = strings.ToLower(e.Matcher.Literal.Value)
+					prefixed = e.Matcher.Literal.Prefix
+				}
+			}
+		}
+
+		switch {
+		case path != "" && prefixed:
+			entry.prefix = path
+			idx.prefixed = append(idx.prefixed, entry)
+		case path != "":
+			idx.exact[path] = append(idx.exact[path], entry)
+		default:
+			idx.others = append(idx.others, entry)
+		}
+	}
+
+	return idx
+}
+
+// each calls fn, in priority order, for every enabled Mock that may match the given method and path,
+// until fn returns false.
+func (idx *mockIndex) each(method, path string, fn func(*Mock) bool) {
+	method = strings.ToUpper(method)
+	path = strings.ToLower(path)
+
+	exact := idx.exact[path]
+	i, j, k := 0, 0, 0
+
+	for {
+		var entry indexEntry
+
+		switch {
+		case i < len(exact) &&
+			(j >= len(idx.prefixed) || exact[i].pos < idx.prefixed[j].pos) &&
+			(k >= len(idx.others) || exact[i].pos < idx.others[k].pos):
+			entry = exact[i]
+			i++
+		case j < len(idx.prefixed) &&
+			(k >= len(idx.others) || idx.prefixed[j].pos < idx.others[k].pos):
+			entry = idx.prefixed[j]
+			j++
+		case k < len(idx.others):
+			entry = idx.others[k]
+			k++
+		default:
+			return
+		}
+
+		if !entry.mock.Enabled ||
+			(entry.method != "" && entry.method != method) ||
+			(entry.prefix != "" && !strings.HasPrefix(path, entry.prefix)) {
+			continue
+		}
+
+		if !fn(entry.mock) {
+			return
+		}
+	}
+}
@@ -1,10 +1,13 @@
 package mocha
 
 import (
+	"net/http"
 	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
 )
 
 func TestInMemoryStorage(t *testing.T) {
@@ -26,6 +29,8 @@ func TestInMemoryStorage(t *testing.T) {
 	assert.Len(t, mocks, 3)
 
 	st.Delete(2)
+	assert.Equal(t, []int{1, 2, 3}, []int{mocks[0].ID, mocks[1].ID, mocks[2].ID}, "fetched lists must not change on delete")
+
 	mocks = st.FetchAll()
 	assert.Len(t, mocks, 2)
 
@@ -39,3 +44,58 @@ func TestInMemoryStorage(t *testing.T) {
 
 	assert.Len(t, mocks, 0)
 }
+
+func TestInMemoryStorage_ForEachEligible(t *testing.T) {
+	build := func(b *MockBuilder, priority int) *Mock {
+		return b.Priority(priority).Build()
+	}
+
+	st := newStorage()
+	exact := build(Get(expect.URLPath("/users")), 3)
+	other := build(Post(expect.URLPath("/users")), 0)
+	prefixed := build(Request().URL(urlPathMatcher(expect.ToHavePrefix("/Users/"))), 2)
+	wildcard := build(Request().URL(expect.Func(func(v any, a expect.Args) (bool, error) { return true, nil })), 1)
+	disabled := build(Get(expect.URLPath("/users")), 0)
+	disabled.Disable()
+
+	for _, m := range []*Mock{exact, other, prefixed, wildcard, disabled} {
+		st.Save(m)
+	}
+
+	collect := func(method, path string) []*Mock {
+		mocks := make([]*Mock, 0)
+		st.ForEachEligible(method, path, func(m *Mock) bool {
+			mocks = append(mocks, m)
+			return true
+		})
+
+		return mocks
+	}
+
+	assert.Equal(t, []*Mock{wildcard, exact}, collect(http.MethodGet, "/users"))
+	assert.Equal(t, []*Mock{wildcard, exact}, collect("get", "/USERS"))
+	assert.Equal(t, []*Mock{other, wildcard}, collect(http.MethodPost, "/users"))
+	assert.Equal(t, []*Mock{wildcard, prefixed}, collect(http.MethodGet, "/users/10"))
+	assert.Equal(t, []*Mock{wildcard}, collect(http.MethodGet, "/orders"))
+
+	t.Run("should stop when fn returns false", func(t *testing.T) {
+		calls := 0
+		st.ForEachEligible(http.MethodGet, "/users", func(m *Mock) bool {
+			calls++
+			return false
+		})
+
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should rebuild the index on changes", func(t *testing.T) {
+		disabled.Enable()
+		assert.Equal(t, []*Mock{disabled, wildcard, exact}, collect(http.MethodGet, "/users"))
+
+		st.Delete(wildcard.ID)
+		assert.Equal(t, []*Mock{disabled, exact}, collect(http.MethodGet, "/users"))
+
+		st.Flush()
+		assert.Len(t, collect(http.MethodGet, "/users"), 0)
+	})
+}
@@ -0,0 +1,87 @@
+package mocha
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// strictLog keeps the requests that did not match any mock or failed to be served.
+// It is only used when Config.Strict is enabled.
+type strictLog struct {
+	entries []*RecordedRequest
+	mu      sync.Mutex
+}
+
+func newStrictLog() *strictLog {
+	return &strictLog{entries: make([]*RecordedRequest, 0)}
+}
+
+// Record adds the given entry to the log if the request was not served by a mock.
+// Requests forwarded to the record target are served on purpose, so they are not logged.
+func (s *strictLog) Record(entry *RecordedRequest) {
+	if (entry.Matched || entry.Forwarded) && entry.Err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, entry)
+}
+
+// Flush returns all entries, removing them from the log.
+func (s *strictLog) Flush() []*RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.entries
+	s.entries = make([]*RecordedRequest, 0)
+
+	return entries
+}
+
+// reportStrict fails the test for each request that was not served by a mock, when strict mode is enabled.
+func (m *Mocha) reportStrict() {
+	if m.strict == nil {
+		return
+	}
+
+	m.t.Helper()
+
+	for _, entry := range m.strict.Flush() {
+		m.t.Errorf("\n%s", describeStrictFailure(entry))
+	}
+}
+
+func describeStrictFailure(entry *RecordedRequest) string {
+	builder := strings.Builder{}
+	r := entry.Request
+
+	if entry.Err != nil {
+		builder.WriteString(fmt.Sprintf("strict mode: an error occurred serving request %s %s\n", r.Method, r.URL))
+		builder.WriteString(fmt.Sprintf("Error: %v\n", entry.Err))
+
+		return builder.String()
+	}
+
+	builder.WriteString(fmt.Sprintf("strict mode: request %s %s did not match any mock\n", r.Method, r.URL))
+
+	if entry.Mismatch == nil {
+		return builder.String()
+	}
+
+	if entry.Mismatch.HasClosestMatch {
+		builder.WriteString(fmt.Sprintf("Closest Match: %d %s\n",
+			entry.Mismatch.ClosestMatch.ID, entry.Mismatch.ClosestMatch.Name))
+	}
+
+	builder.WriteString("Mismatches:\n")
+
+	for _, detail := range entry.Mismatch.Details {
+		builder.WriteString(fmt.Sprintf("%s, reason=%s, applied-to=%s\n",
+			detail.Name, detail.Description, detail.Target))
+	}
+
+	return builder.String()
+}
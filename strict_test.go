@@ -0,0 +1,119 @@
+package mocha
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/testmocks"
+	"github.com/vitorsalgado/mocha/v3/internal/testutil"
+	"github.com/vitorsalgado/mocha/v3/params"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+type fakeCleanable struct{ fn func() }
+
+func (c *fakeCleanable) Cleanup(fn func()) { c.fn = fn }
+
+func TestStrict(t *testing.T) {
+	fakeT := testmocks.NewFakeNotifier()
+	cleanable := &fakeCleanable{}
+
+	m := New(fakeT, Configure().Strict().LogVerbosity(LogSilently).Build()).CloseOnCleanup(cleanable)
+	m.Start()
+
+	m.AddMocks(
+		Get(expect.URLPath("/test")).Name("test").Header("x-test", expect.ToEqual("ok")).Reply(reply.OK()),
+		Get(expect.URLPath("/error")).
+			ReplyFunction(func(r *http.Request, m reply.M, p params.P) (*reply.Response, error) {
+				return nil, fmt.Errorf("failed to build a response")
+			}))
+
+	for _, path := range []string{"/test", "/error"} {
+		res, err := testutil.Get(m.URL() + path).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+	}
+
+	res, err := testutil.Get(m.URL()+"/test").Header("x-test", "ok").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	fakeT.AssertNotCalled(t, "Errorf", mock.Anything, mock.Anything)
+
+	cleanable.fn()
+
+	fakeT.AssertNumberOfCalls(t, "Errorf", 2)
+
+	reports := make([]string, 0)
+	for _, call := range fakeT.Calls {
+		if call.Method == "Errorf" {
+			reports = append(reports, fmt.Sprintf(call.Arguments[0].(string), call.Arguments[1].([]any)...))
+		}
+	}
+
+	assert.True(t, strings.Contains(reports[0], "request GET /test did not match any mock"))
+	assert.True(t, strings.Contains(reports[0], "Closest Match"))
+	assert.True(t, strings.Contains(reports[0], " test\n"))
+	assert.True(t, strings.Contains(reports[0], "applied-to=header"))
+	assert.True(t, strings.Contains(reports[1], "an error occurred serving request GET /error"))
+	assert.True(t, strings.Contains(reports[1], "failed to build a response"))
+}
+
+func TestStrict_Record(t *testing.T) {
+	target := newRecordTarget()
+	defer target.Close()
+
+	fakeT := testmocks.NewFakeNotifier()
+	cleanable := &fakeCleanable{}
+
+	m := New(fakeT, Configure().
+		Strict().
+		Record(RecordConfig{Dir: t.TempDir(), Target: target.URL}).
+		LogVerbosity(LogSilently).
+		Build()).CloseOnCleanup(cleanable)
+	m.Start()
+
+	res, err := testutil.Get(m.URL() + "/hello").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	cleanable.fn()
+
+	fakeT.AssertNotCalled(t, "Errorf", mock.Anything, mock.Anything)
+}
+
+func TestStrict_Disabled(t *testing.T) {
+	fakeT := testmocks.NewFakeNotifier()
+	cleanable := &fakeCleanable{}
+
+	m := New(fakeT, Configure().LogVerbosity(LogSilently).Build()).CloseOnCleanup(cleanable)
+	m.Start()
+
+	res, err := testutil.Get(m.URL() + "/test").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	cleanable.fn()
+
+	fakeT.AssertNotCalled(t, "Errorf", mock.Anything, mock.Anything)
+}
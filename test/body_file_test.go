@@ -0,0 +1,56 @@
+package test
+
+import (
+	"embed"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+//go:embed _testdata
+var testdata embed.FS
+
+func TestBodyFile(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	m.AddMocks(
+		mocha.Get(expect.URLPath("/users")).
+			Reply(reply.OK().BodyFile("_testdata/users.json")),
+		mocha.Get(expect.URLPath("/users/10")).
+			Reply(reply.OK().BodyFS(testdata, "_testdata/user.json.tmpl")))
+
+	testCases := []struct {
+		path string
+		body string
+	}{
+		{"/users", "[{\"id\": 1, \"name\": \"dev\"}, {\"id\": 2, \"name\": \"qa\"}]\n"},
+		{"/users/10", "{\"id\": \"10\"}\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			res, err := http.Get(m.URL() + tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer res.Body.Close()
+
+			b, err := io.ReadAll(res.Body)
+
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "application/json", res.Header.Get(headers.ContentType))
+			assert.Equal(t, tc.body, string(b))
+		})
+	}
+}
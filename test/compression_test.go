@@ -0,0 +1,161 @@
+package test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/internal/mimetypes"
+	"github.com/vitorsalgado/mocha/v3/params"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func TestCompressedRequests(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	jsonScoped := m.AddMocks(mocha.Post(expect.URLPath("/json")).
+		Body(expect.JSONPath("name", expect.ToEqual("dev"))).
+		Reply(reply.OK()))
+	formScoped := m.AddMocks(mocha.Post(expect.URLPath("/form")).
+		FormField("name", expect.ToEqual("dev")).
+		Reply(reply.OK()))
+
+	compress := func(encoding string, content []byte) []byte {
+		buf := &bytes.Buffer{}
+
+		var w io.WriteCloser
+		switch encoding {
+		case "gzip":
+			w = gzip.NewWriter(buf)
+		case "deflate":
+			w = zlib.NewWriter(buf)
+		case "raw deflate":
+			w, _ = flate.NewWriter(buf, flate.DefaultCompression)
+		}
+
+		_, _ = w.Write(content)
+		_ = w.Close()
+
+		return buf.Bytes()
+	}
+
+	post := func(path, contentType, encoding string, body []byte) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, m.URL()+path, bytes.NewReader(body))
+		req.Header.Set(headers.ContentType, contentType)
+		req.Header.Set(headers.ContentEncoding, encoding)
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		return res
+	}
+
+	jsonBody := []byte(`{"name": "dev"}`)
+	formBody := []byte(url.Values{"name": []string{"dev"}}.Encode())
+
+	for _, encoding := range []string{"gzip", "deflate", "raw deflate"} {
+		t.Run(encoding, func(t *testing.T) {
+			header := encoding
+			if encoding == "raw deflate" {
+				header = "deflate"
+			}
+
+			res := post("/json", mimetypes.JSON, header, compress(encoding, jsonBody))
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+
+			res = post("/form", mimetypes.FormURLEncoded, header, compress(encoding, formBody))
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+		})
+	}
+
+	t.Run("multiple encodings", func(t *testing.T) {
+		res := post("/json", mimetypes.JSON, "deflate, gzip", compress("gzip", compress("deflate", jsonBody)))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	assert.Equal(t, 4, jsonScoped.Hits())
+	assert.Equal(t, 3, formScoped.Hits())
+
+	t.Run("should fail with invalid content", func(t *testing.T) {
+		res := post("/json", mimetypes.JSON, "gzip", jsonBody)
+		assert.Equal(t, http.StatusTeapot, res.StatusCode)
+	})
+
+	t.Run("should not parse unsupported encodings", func(t *testing.T) {
+		res := post("/json", mimetypes.JSON, "br", jsonBody)
+		assert.Equal(t, http.StatusTeapot, res.StatusCode)
+	})
+
+	assert.Equal(t, 4, jsonScoped.Hits())
+
+	t.Run("should keep the content encoding header for matchers and remove it for replies", func(t *testing.T) {
+		m.ResetRequests()
+
+		var replyEncoding string
+		var replyBody []byte
+
+		scoped := m.AddMocks(mocha.Post(expect.URLPath("/encoded")).
+			Header(headers.ContentEncoding, expect.ToEqual("gzip")).
+			ReplyFunction(func(r *http.Request, _ reply.M, _ params.P) (*reply.Response, error) {
+				replyEncoding = r.Header.Get(headers.ContentEncoding)
+				replyBody, _ = io.ReadAll(r.Body)
+
+				return &reply.Response{Status: http.StatusOK}, nil
+			}))
+
+		res := post("/encoded", mimetypes.JSON, "gzip", compress("gzip", jsonBody))
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.True(t, scoped.Called())
+		assert.Empty(t, replyEncoding)
+		assert.Equal(t, jsonBody, replyBody)
+		assert.Equal(t, "gzip", m.Requests()[0].Request.Header.Get(headers.ContentEncoding))
+	})
+}
+
+func TestCompressedReplies(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	m.AddMocks(mocha.Get(expect.URLPath("/test")).
+		Reply(reply.OK().BodyString("hello world").Compress()))
+
+	// the same body must be compressed on every request.
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, m.URL()+"/test", nil)
+		req.Header.Set(headers.AcceptEncoding, "deflate;q=0.5, gzip")
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reader, err := gzip.NewReader(res.Body)
+		assert.Nil(t, err)
+
+		b, err := io.ReadAll(reader)
+		res.Body.Close()
+
+		assert.Nil(t, err)
+		assert.Equal(t, "gzip", res.Header.Get(headers.ContentEncoding))
+		assert.Equal(t, headers.AcceptEncoding, res.Header.Get(headers.Vary))
+		assert.Equal(t, "hello world", string(b))
+	}
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/testutil"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func TestGraphQL(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	getUser := m.AddMocks(mocha.Post(expect.URLPath("/graphql")).
+		Body(expect.GraphQLOperation("GetUser")).
+		Body(expect.GraphQLVariables("id", expect.ToEqual("1"))).
+		Reply(reply.GraphQLData(map[string]any{"user": map[string]any{"id": "1", "name": "dev"}})))
+
+	deleteUser := m.AddMocks(mocha.Post(expect.URLPath("/graphql")).
+		Body(expect.GraphQLOperationType("mutation")).
+		Body(expect.GraphQLField("deleteUser")).
+		Reply(reply.GraphQL().Data(map[string]any{"deleteUser": nil}).Error("forbidden", "deleteUser")))
+
+	call := func(body map[string]any) map[string]any {
+		res, err := testutil.PostJSON(m.URL()+"/graphql", body).Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var payload map[string]any
+		assert.Nil(t, json.NewDecoder(res.Body).Decode(&payload))
+
+		return payload
+	}
+
+	payload := call(map[string]any{
+		"query":         "query GetUser($id: ID!) { user(id: $id) { id name } }",
+		"operationName": "GetUser",
+		"variables":     map[string]any{"id": "1"}})
+
+	assert.Equal(t, map[string]any{"user": map[string]any{"id": "1", "name": "dev"}}, payload["data"])
+	assert.Nil(t, payload["errors"])
+
+	payload = call(map[string]any{"query": `mutation { deleteUser(id: "1") }`})
+
+	assert.Equal(t, map[string]any{"deleteUser": nil}, payload["data"])
+	assert.Len(t, payload["errors"], 1)
+
+	assert.True(t, getUser.Called())
+	assert.True(t, deleteUser.Called())
+
+	res, err := testutil.PostJSON(m.URL()+"/graphql", map[string]any{
+		"query": "query GetUser($id: ID!) { user(id: $id) { id } }", "variables": map[string]any{"id": "2"}}).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusTeapot, res.StatusCode)
+}
@@ -0,0 +1,311 @@
+package test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/interop/grpc_testing"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/grpcmock"
+)
+
+func dialGRPC(t *testing.T, m *mocha.Mocha, config ...grpcmock.Config) *grpc.ClientConn {
+	addr := grpcmock.New(t, m, config...).CloseOnCleanup(t).Start()
+
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func grpcContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	return ctx
+}
+
+func TestGRPC_Unary(t *testing.T) {
+	m := mocha.New(t)
+	m.CloseOnCleanup(t)
+
+	scoped := m.AddMocks(grpcmock.Method("grpc.testing.TestService/UnaryCall").
+		Header("x-user", expect.ToEqual("dev")).
+		Body(expect.JSONPath("response_size", expect.ToEqual(float64(10)))).
+		Reply(grpcmock.OK().
+			Message(&grpc_testing.SimpleResponse{Username: "dev", Hostname: "local"}).
+			Header("x-header", "hello").
+			Trailer("x-trailer", "bye")))
+
+	conn := dialGRPC(t, m)
+	client := grpc_testing.NewTestServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(grpcContext(t), "x-user", "dev")
+
+	var header, trailer metadata.MD
+	res, err := client.UnaryCall(ctx,
+		&grpc_testing.SimpleRequest{ResponseSize: 10},
+		grpc.Header(&header), grpc.Trailer(&trailer))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "dev", res.Username)
+	assert.Equal(t, "local", res.Hostname)
+	assert.Equal(t, []string{"hello"}, header.Get("x-header"))
+	assert.Equal(t, []string{"bye"}, trailer.Get("x-trailer"))
+	assert.True(t, scoped.Called())
+	assert.Equal(t, 1, scoped.Hits())
+	assert.True(t, m.Verify(t, grpcmock.Method("/grpc.testing.TestService/UnaryCall"), mocha.Times(1)))
+
+	t.Run("should reply status unimplemented when request does not match", func(t *testing.T) {
+		_, err := client.UnaryCall(ctx, &grpc_testing.SimpleRequest{ResponseSize: 20})
+
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.Unimplemented, st.Code())
+		assert.Contains(t, st.Message(), "JSONPath")
+		assert.Equal(t, 1, scoped.Hits())
+	})
+
+	t.Run("should reply status unimplemented for unknown methods", func(t *testing.T) {
+		err := conn.Invoke(ctx, "/unknown.Service/Call", &grpc_testing.Empty{}, &grpc_testing.Empty{})
+
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.Unimplemented, st.Code())
+	})
+
+	t.Run("should not count hits for unknown methods with mocks", func(t *testing.T) {
+		unknown := m.AddMocks(grpcmock.Method("/unknown.Service/Call").Reply(grpcmock.OK()))
+
+		err := conn.Invoke(ctx, "/unknown.Service/Call", &grpc_testing.Empty{}, &grpc_testing.Empty{})
+
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+		assert.False(t, unknown.Called())
+	})
+}
+
+func TestGRPC_UnaryWithJSONMessage(t *testing.T) {
+	m := mocha.New(t)
+	m.CloseOnCleanup(t)
+
+	m.AddMocks(grpcmock.Method("/grpc.testing.TestService/UnaryCall").
+		Reply(grpcmock.OK().Message(map[string]any{"username": "json", "oauth_scope": "read"})))
+
+	client := grpc_testing.NewTestServiceClient(dialGRPC(t, m))
+	res, err := client.UnaryCall(grpcContext(t), &grpc_testing.SimpleRequest{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "json", res.Username)
+	assert.Equal(t, "read", res.OauthScope)
+}
+
+func TestGRPC_ErrorStatus(t *testing.T) {
+	m := mocha.New(t)
+	m.CloseOnCleanup(t)
+
+	m.AddMocks(grpcmock.Method("/grpc.testing.TestService/UnaryCall").
+		Reply(grpcmock.Error(codes.NotFound, "user not found").
+			Details(&errdetails.ErrorInfo{Reason: "USER_NOT_FOUND", Domain: "mocha"})))
+
+	client := grpc_testing.NewTestServiceClient(dialGRPC(t, m))
+	_, err := client.UnaryCall(grpcContext(t), &grpc_testing.SimpleRequest{})
+
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "user not found", st.Message())
+	assert.Len(t, st.Details(), 1)
+
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	assert.True(t, ok)
+	assert.Equal(t, "USER_NOT_FOUND", info.Reason)
+}
+
+func TestGRPC_ServerStreaming(t *testing.T) {
+	m := mocha.New(t)
+	m.CloseOnCleanup(t)
+
+	m.AddMocks(grpcmock.Method("/grpc.testing.TestService/StreamingOutputCall").
+		Body(expect.JSONPath("payload.body", expect.ToEqual("aGVsbG8="))).
+		Reply(grpcmock.OK().
+			Message(&grpc_testing.StreamingOutputCallResponse{Payload: &grpc_testing.Payload{Body: []byte("one")}}).
+			Message(`{"payload": {"body": "dHdv"}}`).
+			Status(codes.Aborted, "stream aborted")))
+
+	client := grpc_testing.NewTestServiceClient(dialGRPC(t, m))
+	stream, err := client.StreamingOutputCall(grpcContext(t),
+		&grpc_testing.StreamingOutputCallRequest{Payload: &grpc_testing.Payload{Body: []byte("hello")}})
+	assert.Nil(t, err)
+
+	res, err := stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, "one", string(res.Payload.Body))
+
+	res, err = stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, "two", string(res.Payload.Body))
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Aborted, status.Code(err))
+}
+
+func TestGRPC_ClientStreaming(t *testing.T) {
+	m := mocha.New(t)
+	m.CloseOnCleanup(t)
+
+	scoped := m.AddMocks(grpcmock.Method("/grpc.testing.TestService/StreamingInputCall").
+		Body(expect.ToHaveLen(3)).
+		Reply(grpcmock.OK().Message(&grpc_testing.StreamingInputCallResponse{AggregatedPayloadSize: 3})))
+
+	client := grpc_testing.NewTestServiceClient(dialGRPC(t, m))
+	stream, err := client.StreamingInputCall(grpcContext(t))
+	assert.Nil(t, err)
+
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, stream.Send(&grpc_testing.StreamingInputCallRequest{Payload: &grpc_testing.Payload{Body: []byte{byte(i)}}}))
+	}
+
+	res, err := stream.CloseAndRecv()
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), res.AggregatedPayloadSize)
+	assert.True(t, scoped.Called())
+}
+
+func TestGRPC_BidiStreaming(t *testing.T) {
+	m := mocha.New(t)
+	m.CloseOnCleanup(t)
+
+	ping := m.AddMocks(grpcmock.Method("/grpc.testing.TestService/FullDuplexCall").
+		Body(expect.JSONPath("payload.body", expect.ToEqual("AQ=="))).
+		Reply(grpcmock.OK().
+			Message(map[string]any{"payload": map[string]any{"body": "Ag=="}}).
+			Message(map[string]any{"payload": map[string]any{"body": "Aw=="}})))
+	pong := m.AddMocks(grpcmock.Method("/grpc.testing.TestService/FullDuplexCall").
+		Body(expect.JSONPath("payload.body", expect.ToEqual("BA=="))).
+		Reply(grpcmock.OK().Message(map[string]any{"payload": map[string]any{"body": "BQ=="}})))
+
+	client := grpc_testing.NewTestServiceClient(dialGRPC(t, m))
+	stream, err := client.FullDuplexCall(grpcContext(t))
+	assert.Nil(t, err)
+
+	recv := func() byte {
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return res.Payload.Body[0]
+	}
+
+	// each message is replied before the client sends the next one.
+	assert.Nil(t, stream.Send(&grpc_testing.StreamingOutputCallRequest{Payload: &grpc_testing.Payload{Body: []byte{1}}}))
+	assert.Equal(t, byte(2), recv())
+	assert.Equal(t, byte(3), recv())
+
+	assert.Nil(t, stream.Send(&grpc_testing.StreamingOutputCallRequest{Payload: &grpc_testing.Payload{Body: []byte{4}}}))
+	assert.Equal(t, byte(5), recv())
+
+	assert.Nil(t, stream.CloseSend())
+
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 1, ping.Hits())
+	assert.Equal(t, 1, pong.Hits())
+
+	t.Run("should end the stream when a message does not match", func(t *testing.T) {
+		stream, err := client.FullDuplexCall(grpcContext(t))
+		assert.Nil(t, err)
+
+		assert.Nil(t, stream.Send(&grpc_testing.StreamingOutputCallRequest{Payload: &grpc_testing.Payload{Body: []byte{9}}}))
+
+		_, err = stream.Recv()
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
+
+func TestGRPC_StartTwice(t *testing.T) {
+	m := mocha.New(t)
+	m.CloseOnCleanup(t)
+
+	srv := grpcmock.New(t, m).CloseOnCleanup(t)
+	addr := srv.Start()
+
+	assert.Equal(t, addr, srv.Start())
+	assert.Equal(t, addr, srv.Addr())
+
+	srv.Close()
+
+	assert.Empty(t, srv.Addr())
+}
+
+func TestGRPC_DescriptorSet(t *testing.T) {
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("greeter.proto"),
+		Package: proto.String("greeter.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("HelloRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("name"), Number: proto.Int32(1), Type: &str, Label: &optional, JsonName: proto.String("name")}}},
+			{Name: proto.String("HelloReply"), Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("message"), Number: proto.Int32(1), Type: &str, Label: &optional, JsonName: proto.String("message")}}},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{Name: proto.String("Greeter"), Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("SayHello"), InputType: proto.String(".greeter.v1.HelloRequest"), OutputType: proto.String(".greeter.v1.HelloReply")}}},
+		},
+	}
+
+	b, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "greeter.pb")
+	if err = os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := mocha.New(t)
+	m.CloseOnCleanup(t)
+
+	scoped := m.AddMocks(grpcmock.Method("/greeter.v1.Greeter/SayHello").
+		Body(expect.JSONPath("name", expect.ToEqual("mocha"))).
+		Reply(grpcmock.OK().Message(`{"message": "hello mocha"}`)))
+
+	fd, err := protodesc.NewFile(file, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := dynamicpb.NewMessage(fd.Messages().ByName("HelloRequest"))
+	req.Set(fd.Messages().ByName("HelloRequest").Fields().ByName("name"), protoreflect.ValueOfString("mocha"))
+	res := dynamicpb.NewMessage(fd.Messages().ByName("HelloReply"))
+
+	conn := dialGRPC(t, m, grpcmock.Config{DescriptorSets: []string{path}})
+	err = conn.Invoke(grpcContext(t), "/greeter.v1.Greeter/SayHello", req, res)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "hello mocha", res.Get(fd.Messages().ByName("HelloReply").Fields().ByName("message")).String())
+	assert.True(t, scoped.Called())
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/internal/mimetypes"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func TestJSONPath(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	scoped := m.AddMocks(mocha.Post(expect.URLPath("/orders")).
+		Body(expect.JSONPath("items[?(@.status == 'open')].id", expect.ToEqual([]any{float64(1), float64(3)}))).
+		Body(expect.JSONPathAll("items[*].price", expect.Func(func(v any, _ expect.Args) (bool, error) {
+			return v.(float64) > 0, nil
+		}))).
+		Body(expect.JSONPathAny("$..tags[*]", expect.ToEqual("urgent"))).
+		Body(expect.JSONPath("['customer.id']", expect.ToEqual("c1"))).
+		Reply(reply.Created()))
+
+	post := func(body string) int {
+		req, _ := http.NewRequest(http.MethodPost, m.URL()+"/orders", strings.NewReader(body))
+		req.Header.Set(headers.ContentType, mimetypes.JSON)
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		return res.StatusCode
+	}
+
+	assert.Equal(t, http.StatusCreated, post(`{
+		"customer.id": "c1",
+		"items": [
+			{"id": 1, "status": "open", "price": 10, "tags": ["new"]},
+			{"id": 2, "status": "closed", "price": 5},
+			{"id": 3, "status": "open", "price": 7, "tags": ["urgent"]}
+		]
+	}`))
+
+	assert.Equal(t, http.StatusTeapot, post(`{
+		"customer.id": "c1",
+		"items": [
+			{"id": 1, "status": "open", "price": 10, "tags": ["new"]},
+			{"id": 3, "status": "open", "price": 0, "tags": ["urgent"]}
+		]
+	}`))
+
+	assert.Equal(t, 1, scoped.Hits())
+}
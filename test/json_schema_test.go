@@ -0,0 +1,60 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/internal/mimetypes"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func TestJSONSchema(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	scoped := m.AddMocks(mocha.Post(expect.URLPath("/users")).
+		Body(expect.ToMatchJSONSchema(`{
+			"type": "object",
+			"required": ["name", "address"],
+			"properties": {"name": {"type": "string"}, "age": {"type": "integer", "minimum": 0}}
+		}`)).
+		Body(expect.JSONPath("address", expect.ToMatchJSONSchema(`{"required": ["city"]}`))).
+		Reply(reply.Created()))
+
+	post := func(body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, m.URL()+"/users", strings.NewReader(body))
+		req.Header.Set(headers.ContentType, mimetypes.JSON)
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return res
+	}
+
+	res := post(`{"name": "dev", "age": 30, "address": {"city": "Santiago"}}`)
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Equal(t, 1, scoped.Hits())
+
+	res = post(`{"name": 10, "age": -1, "address": {"city": "Santiago"}}`)
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusTeapot, res.StatusCode)
+	assert.Contains(t, string(b), "#/age: value -1 is less than the minimum 0")
+	assert.Contains(t, string(b), "#/name: expected type string, got integer")
+	assert.Equal(t, 1, scoped.Hits())
+}
@@ -0,0 +1,192 @@
+package test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func newMultipartRequest(t *testing.T, url string, fields map[string]string, filename, content string) *http.Request {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+
+	for k, v := range fields {
+		assert.Nil(t, w.WriteField(k, v))
+	}
+
+	if filename != "" {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", `form-data; name="document"; filename="`+filename+`"`)
+		h.Set("Content-Type", "text/plain")
+
+		part, err := w.CreatePart(h)
+		assert.Nil(t, err)
+
+		_, err = part.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, w.Close())
+
+	req, _ := http.NewRequest(http.MethodPost, url, body)
+	req.Header.Set(headers.ContentType, w.FormDataContentType())
+
+	return req
+}
+
+func TestMultipart(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	scoped := m.AddMocks(mocha.Post(expect.URLPath("/upload")).
+		MultipartField("description", expect.ToEqual("monthly report")).
+		MultipartFile("document", expect.AllOf(
+			expect.JSONPath("filename", expect.ToHaveSuffix(".txt")),
+			expect.JSONPath("content_type", expect.ToEqual("text/plain")),
+			expect.JSONPath("size", expect.ToEqual(11)),
+			expect.JSONPath("content", expect.ToContain("hello")))).
+		Reply(reply.Created()))
+
+	req := newMultipartRequest(t, m.URL()+"/upload",
+		map[string]string{"description": "monthly report"}, "report.txt", "hello world")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.True(t, scoped.Called())
+
+	t.Run("should not match when the file is different", func(t *testing.T) {
+		req := newMultipartRequest(t, m.URL()+"/upload",
+			map[string]string{"description": "monthly report"}, "report.csv", "hello world")
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusTeapot, res.StatusCode)
+		assert.Equal(t, 1, scoped.Hits())
+	})
+
+	t.Run("should not match when the field is missing", func(t *testing.T) {
+		req := newMultipartRequest(t, m.URL()+"/upload", map[string]string{"other": "value"}, "report.txt", "hello world")
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusTeapot, res.StatusCode)
+		assert.Equal(t, 1, scoped.Hits())
+	})
+}
+
+func TestMultipart_NonMultipartRequests(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	field := m.AddMocks(mocha.Post(expect.URLPath("/upload")).
+		Priority(1).
+		MultipartField("x", expect.ToBeEmpty()).
+		Reply(reply.Created()))
+	file := m.AddMocks(mocha.Post(expect.URLPath("/upload")).
+		Priority(2).
+		MultipartFile("document", expect.JSONPath("filename", expect.ToHaveSuffix(".txt"))).
+		Reply(reply.Created()))
+	fallback := m.AddMocks(mocha.Post(expect.URLPath("/upload")).
+		Priority(3).
+		Reply(reply.Accepted()))
+
+	req, _ := http.NewRequest(http.MethodPost, m.URL()+"/upload", bytes.NewReader([]byte(`{"x": "abc"}`)))
+	req.Header.Set(headers.ContentType, "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusAccepted, res.StatusCode)
+	assert.False(t, field.Called())
+	assert.False(t, file.Called())
+	assert.True(t, fallback.Called())
+
+	req = newMultipartRequest(t, m.URL()+"/upload", map[string]string{"y": "value"}, "", "")
+
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusAccepted, res.StatusCode)
+	assert.Equal(t, 2, fallback.Hits())
+
+	req = newMultipartRequest(t, m.URL()+"/upload", map[string]string{"x": ""}, "", "")
+
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.True(t, field.Called())
+}
+
+func TestMultipart_ParsedBody(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	m.AddMocks(mocha.Post(expect.URLPath("/upload")).
+		Body(expect.Func(func(v any, _ expect.Args) (bool, error) {
+			form, ok := v.(*mocha.MultipartForm)
+			if !ok {
+				return false, nil
+			}
+
+			file := form.File("document")
+
+			return form.Fields.Get("a") == "1" &&
+				form.Fields.Get("b") == "2" &&
+				file != nil &&
+				file.Filename == "data.txt" &&
+				string(file.Content) == "content", nil
+		})).
+		Reply(reply.OK()))
+
+	req := newMultipartRequest(t, m.URL()+"/upload", map[string]string{"a": "1", "b": "2"}, "data.txt", "content")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
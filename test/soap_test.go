@@ -0,0 +1,123 @@
+package test
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+type soapUserResponse struct {
+	XMLName xml.Name `xml:"urn:users GetUserResponse"`
+	Name    string   `xml:"name"`
+}
+
+func TestSOAP(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	scoped := m.AddMocks(
+		mocha.SOAP("GetUser").
+			URL(expect.URLPath("/users")).
+			SOAPAction("urn:users/GetUser").
+			Body(expect.XPath("//GetUser/id", expect.ToEqual("1"))).
+			Reply(reply.SOAP().Body(soapUserResponse{Name: "dev"})),
+		mocha.SOAP("GetUser").
+			URL(expect.URLPath("/users")).
+			SOAPAction("urn:users/GetUser").
+			Body(expect.XPath("//GetUser/id", expect.ToEqual("2"))).
+			Reply(reply.SOAP().Version(reply.SOAP12).Fault(reply.SOAPFault{
+				Code:   reply.SOAPFaultSender,
+				Reason: "user not found",
+			})))
+
+	post := func(body string, header map[string]string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, m.URL()+"/users", strings.NewReader(body))
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return res
+	}
+
+	envelope := func(ns, id string) string {
+		return `<soap:Envelope xmlns:soap="` + ns + `"><soap:Body>` +
+			`<u:GetUser xmlns:u="urn:users"><id>` + id + `</id></u:GetUser>` +
+			`</soap:Body></soap:Envelope>`
+	}
+
+	t.Run("soap 1.1", func(t *testing.T) {
+		res := post(envelope(reply.SOAP11Namespace, "1"), map[string]string{
+			headers.ContentType: "text/xml; charset=utf-8",
+			headers.SOAPAction:  `"urn:users/GetUser"`,
+		})
+		defer res.Body.Close()
+
+		b, err := io.ReadAll(res.Body)
+		assert.Nil(t, err)
+
+		type envelope struct {
+			Body struct {
+				User soapUserResponse `xml:"urn:users GetUserResponse"`
+			} `xml:"Body"`
+		}
+
+		env := envelope{}
+		assert.Nil(t, xml.Unmarshal(b, &env))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/xml; charset=utf-8", res.Header.Get(headers.ContentType))
+		assert.Equal(t, "dev", env.Body.User.Name)
+	})
+
+	t.Run("soap 1.2 fault", func(t *testing.T) {
+		res := post(envelope(reply.SOAP12Namespace, "2"), map[string]string{
+			headers.ContentType: `application/soap+xml; charset=utf-8; action="urn:users/GetUser"`,
+		})
+		defer res.Body.Close()
+
+		b, err := io.ReadAll(res.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		assert.Equal(t, "application/soap+xml; charset=utf-8", res.Header.Get(headers.ContentType))
+		assert.Contains(t, string(b), "<soap:Value>soap:Sender</soap:Value>")
+		assert.Contains(t, string(b), "user not found")
+	})
+
+	assert.Equal(t, 2, scoped.Hits())
+
+	t.Run("should not match other actions", func(t *testing.T) {
+		res := post(envelope(reply.SOAP11Namespace, "1"), map[string]string{
+			headers.ContentType: "text/xml",
+			headers.SOAPAction:  "urn:users/DeleteUser",
+		})
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusTeapot, res.StatusCode)
+	})
+
+	t.Run("should not match other operations", func(t *testing.T) {
+		res := post(strings.ReplaceAll(envelope(reply.SOAP11Namespace, "1"), "GetUser", "DeleteUser"), map[string]string{
+			headers.ContentType: "text/xml",
+			headers.SOAPAction:  "urn:users/GetUser",
+		})
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusTeapot, res.StatusCode)
+	})
+
+	assert.Equal(t, 2, scoped.Hits())
+}
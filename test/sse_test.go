@@ -0,0 +1,62 @@
+package test
+
+import (
+	"bufio"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/testutil"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func TestSSE(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	scoped := m.AddMocks(mocha.Get(expect.URLPath("/events")).
+		Reply(reply.SSE().
+			Event(reply.SSEEvent{ID: "1", Event: "greeting", Data: "hello"}).
+			Event(reply.SSEEvent{ID: "2", Data: "world", Delay: 100 * time.Millisecond}).
+			KeepOpen()))
+
+	res, err := testutil.Get(m.URL() + "/events").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(res.Body)
+	readEvent := func() string {
+		event := ""
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if line == "\n" {
+				return event
+			}
+
+			event += line
+		}
+	}
+
+	start := time.Now()
+
+	assert.Equal(t, "id: 1\nevent: greeting\ndata: hello\n", readEvent())
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, "id: 2\ndata: world\n", readEvent())
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assert.True(t, scoped.Called())
+}
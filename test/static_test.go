@@ -0,0 +1,89 @@
+package test
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+)
+
+func TestStatic(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	assets, err := fs.Sub(testdata, "_testdata/assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local := m.AddMocks(mocha.Static("/local/", os.DirFS("_testdata/assets")))
+	cdn := m.AddMocks(mocha.Static("/cdn/", assets))
+
+	do := func(method, target string, header map[string]string) (*http.Response, string) {
+		req, _ := http.NewRequest(method, m.URL()+target, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer res.Body.Close()
+
+		b, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return res, string(b)
+	}
+
+	for _, prefix := range []string{"/local/", "/cdn/"} {
+		t.Run(prefix, func(t *testing.T) {
+			res, body := do(http.MethodGet, prefix+"css/style.css", nil)
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "text/css; charset=utf-8", res.Header.Get(headers.ContentType))
+			assert.Equal(t, "body { margin: 0; }\n", body)
+
+			etag := res.Header.Get(headers.ETag)
+			assert.NotEmpty(t, etag)
+
+			res, body = do(http.MethodGet, prefix+"css/style.css", map[string]string{headers.IfNoneMatch: etag})
+
+			assert.Equal(t, http.StatusNotModified, res.StatusCode)
+			assert.Empty(t, body)
+
+			res, body = do(http.MethodGet, prefix+"css/style.css", map[string]string{headers.Range: "bytes=0-3"})
+
+			assert.Equal(t, http.StatusPartialContent, res.StatusCode)
+			assert.Equal(t, "body", body)
+
+			res, body = do(http.MethodHead, prefix, nil)
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "text/html; charset=utf-8", res.Header.Get(headers.ContentType))
+			assert.Empty(t, body)
+
+			res, _ = do(http.MethodGet, prefix+"none.js", nil)
+
+			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+			res, _ = do(http.MethodPost, prefix+"css/style.css", nil)
+
+			assert.Equal(t, http.StatusTeapot, res.StatusCode)
+		})
+	}
+
+	assert.Equal(t, 5, local.Hits())
+	assert.Equal(t, 5, cdn.Hits())
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/testutil"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func TestStream(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	m.AddMocks(mocha.Get(expect.URLPath("/stream")).
+		Reply(reply.Stream().
+			Header("content-type", "text/plain").
+			Trailer("x-checksum", "abc").
+			ChunkString("hello", 0).
+			ChunkString(" ", 50*time.Millisecond).
+			ChunkString("world", 50*time.Millisecond)))
+
+	t.Run("should write chunks with trailers", func(t *testing.T) {
+		res, err := testutil.Get(m.URL() + "/stream").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, []string{"chunked"}, res.TransferEncoding)
+
+		first := make([]byte, 5)
+		_, err = io.ReadFull(res.Body, first)
+		assert.Nil(t, err)
+		assert.Equal(t, "hello", string(first))
+
+		start := time.Now()
+		rest, err := io.ReadAll(res.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, " world", string(rest))
+		assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+		assert.Equal(t, "abc", res.Trailer.Get("x-checksum"))
+	})
+
+	t.Run("should allow clients to time out while reading the body", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, m.URL()+"/stream", nil)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer res.Body.Close()
+
+		b, err := io.ReadAll(res.Body)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Equal(t, "hello", string(b))
+	})
+}
@@ -0,0 +1,91 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/internal/mimetypes"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func TestResponseTemplate(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	scoped := m.AddMocks(mocha.Post(expect.URLPath("/users/10/orders")).
+		StartScenario("checkout").
+		ScenarioStateWillBe("ordered").
+		Reply(reply.Created().
+			BodyTemplate(`{{ index .Path 1 }} {{ .Body.item }} {{ mul .Body.quantity .Body.price }} ` +
+				`{{ .Query "ref" }} {{ .Header "X-Tenant" }} {{ .Hits }} {{ .Scenario.Name }} {{ .Scenario.State }} ` +
+				`{{ base64Encode .Body.item }} {{ len uuid }}`)))
+
+	req, _ := http.NewRequest(http.MethodPost, m.URL()+"/users/10/orders?ref=abc",
+		strings.NewReader(`{"item": "book", "quantity": 2, "price": 10.5}`))
+	req.Header.Set(headers.ContentType, mimetypes.JSON)
+	req.Header.Set("X-Tenant", "t1")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+
+	assert.Nil(t, err)
+	assert.True(t, scoped.Called())
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Equal(t, "10 book 21 abc t1 1 checkout ordered Ym9vaw== 36", string(b))
+}
+
+func TestResponseTemplate_StatusAndHeaders(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	m.AddMocks(mocha.Post(expect.URLPath("/users")).
+		Reply(reply.New().
+			StatusTemplate(`{{ if .Body.id }}201{{ else }}400{{ end }}`).
+			HeaderTemplate("Location", "/users/{{ .Body.id }}").
+			HeaderTemplate("X-Correlation-ID", `{{ .Header "X-Correlation-ID" }}`).
+			CookieTemplate(http.Cookie{Name: "user"}, "{{ .Body.id }}")))
+
+	req, _ := http.NewRequest(http.MethodPost, m.URL()+"/users", strings.NewReader(`{"id": "10"}`))
+	req.Header.Set(headers.ContentType, mimetypes.JSON)
+	req.Header.Set("X-Correlation-ID", "abc")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Equal(t, "/users/10", res.Header.Get("Location"))
+	assert.Equal(t, "abc", res.Header.Get("X-Correlation-ID"))
+	assert.Len(t, res.Cookies(), 1)
+	assert.Equal(t, "10", res.Cookies()[0].Value)
+
+	req, _ = http.NewRequest(http.MethodPost, m.URL()+"/users", strings.NewReader(`{}`))
+	req.Header.Set(headers.ContentType, mimetypes.JSON)
+
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
@@ -0,0 +1,176 @@
+package test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/testmocks"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func dialWebSocket(t *testing.T, m *mocha.Mocha, path string, header http.Header) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(m.URL(), "http")+path, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	return conn
+}
+
+func TestWebSocket(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	scoped := m.AddMocks(mocha.WebSocket(expect.URLPath("/ws")).
+		Header("x-token", expect.ToEqual("secret")).
+		Reply(reply.WebSocket().
+			Send(reply.WebSocketText("welcome")).
+			Expect(expect.ToEqual("ping"), reply.WebSocketText("pong")).
+			ExpectJSON(expect.JSONPath("type", expect.ToEqual("subscribe")),
+				reply.WebSocketJSON(map[string]any{"status": "subscribed"}),
+				reply.WebSocketBinary([]byte{0x01, 0x02})).
+			Close(websocket.CloseNormalClosure, "bye")))
+
+	conn := dialWebSocket(t, m, "/ws", http.Header{"x-token": []string{"secret"}})
+	defer conn.Close()
+
+	_, msg, err := conn.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, "welcome", string(msg))
+
+	assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte("ping")))
+
+	_, msg, err = conn.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, "pong", string(msg))
+
+	assert.Nil(t, conn.WriteJSON(map[string]any{"type": "subscribe", "topic": "news"}))
+
+	_, msg, err = conn.ReadMessage()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"status":"subscribed"}`, string(msg))
+
+	mt, msg, err := conn.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, websocket.BinaryMessage, mt)
+	assert.Equal(t, []byte{0x01, 0x02}, msg)
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+	assert.Contains(t, err.Error(), "bye")
+
+	assert.True(t, scoped.Called())
+	assert.Len(t, scoped.WebSocketMessages(), 2)
+	assert.True(t, scoped.AssertWebSocketReceived(t, expect.ToEqual("ping")))
+	assert.True(t, scoped.AssertWebSocketReceived(t, expect.ToContain("news")))
+	assert.True(t, scoped.AssertWebSocketReceived(t, expect.Func(func(v any, args expect.Args) (bool, error) {
+		return v == "ping" && args.RequestInfo.Request.Header.Get("x-token") == "secret", nil
+	})))
+
+	fakeT := testmocks.NewFakeNotifier()
+	assert.False(t, scoped.AssertWebSocketReceived(fakeT, expect.ToEqual("other")))
+	fakeT.AssertNumberOfCalls(t, "Errorf", 1)
+}
+
+func TestWebSocket_Mismatch(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	m.AddMocks(mocha.WebSocket(expect.URLPath("/ws")).
+		Reply(reply.WebSocket().
+			Expect(expect.ToEqual("hello"), reply.WebSocketText("hi"))))
+
+	conn := dialWebSocket(t, m, "/ws", nil)
+	defer conn.Close()
+
+	assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte("bye")))
+
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation))
+
+	t.Run("should not match requests that are not upgrades", func(t *testing.T) {
+		res, err := http.Get(m.URL() + "/ws")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusTeapot, res.StatusCode)
+	})
+}
+
+func TestWebSocket_FailedHandshake(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	scoped := m.AddMocks(mocha.WebSocket(expect.URLPath("/ws")).
+		Reply(reply.WebSocket().Send(reply.WebSocketText("hello"))))
+
+	// the upgrade request is missing the websocket key and version headers.
+	req, _ := http.NewRequest(http.MethodGet, m.URL()+"/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	assert.False(t, scoped.Called())
+
+	requests := m.Requests()
+	assert.Len(t, requests, 1)
+	assert.Equal(t, http.StatusBadRequest, requests[0].Status)
+	assert.NotNil(t, requests[0].Err)
+}
+
+func TestWebSocket_ClosedWithServer(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+
+	scoped := m.AddMocks(mocha.WebSocket(expect.URLPath("/ws")).
+		Reply(reply.WebSocket().Send(reply.WebSocketText("hello"))))
+
+	conn := dialWebSocket(t, m, "/ws", nil)
+	defer conn.Close()
+
+	_, msg, err := conn.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", string(msg))
+
+	assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte("after script")))
+
+	// wait for the message to be received before closing the server.
+	assert.Eventually(t, func() bool { return len(scoped.WebSocketMessages()) == 1 }, time.Second, 10*time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		m.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server should close websocket connections")
+	}
+
+	_, _, err = conn.ReadMessage()
+	assert.NotNil(t, err)
+}
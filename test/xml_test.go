@@ -0,0 +1,80 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3"
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/headers"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+type xmlOrder struct {
+	ID     string `xml:"id,attr"`
+	Status string `xml:"status"`
+}
+
+func TestXML(t *testing.T) {
+	m := mocha.New(t)
+	m.Start()
+	defer m.Close()
+
+	scoped := m.AddMocks(mocha.Post(expect.URLPath("/orders")).
+		Body(expect.XPath("/order/@id", expect.ToEqual("10"))).
+		Body(expect.XPath("count(/order/item)", expect.ToEqual(float64(2)))).
+		Reply(reply.Created().
+			Header(headers.ContentType, "application/xml").
+			BodyXML(xmlOrder{ID: "10", Status: "created"})))
+
+	post := func(contentType, body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, m.URL()+"/orders", strings.NewReader(body))
+		req.Header.Set(headers.ContentType, contentType)
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return res
+	}
+
+	res := post("application/xml", `<order id="10"><item>a</item><item>b</item></order>`)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Equal(t, `<xmlOrder id="10"><status>created</status></xmlOrder>`, string(body))
+
+	for _, contentType := range []string{"text/xml; charset=utf-8", "application/vnd.orders+xml"} {
+		t.Run(contentType, func(t *testing.T) {
+			res := post(contentType, `<order id="10"><item>a</item><item>b</item></order>`)
+			res.Body.Close()
+
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+		})
+	}
+
+	assert.Equal(t, 3, scoped.Hits())
+
+	t.Run("should not match different documents", func(t *testing.T) {
+		res := post("application/xml", `<order id="11"><item>a</item><item>b</item></order>`)
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusTeapot, res.StatusCode)
+	})
+
+	t.Run("should fail with invalid xml", func(t *testing.T) {
+		res := post("application/xml", `<order id="10">`)
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusTeapot, res.StatusCode)
+		assert.Equal(t, 3, scoped.Hits())
+	})
+}
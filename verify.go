@@ -0,0 +1,93 @@
+package mocha
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+)
+
+// Count defines a constraint on the number of received requests, used by Verify.
+// Use Times, AtLeast, AtMost or Never to create one.
+type Count struct {
+	min int
+	max int
+}
+
+// Times requires exactly n matching requests.
+func Times(n int) Count { return Count{min: n, max: n} }
+
+// AtLeast requires n or more matching requests.
+func AtLeast(n int) Count { return Count{min: n, max: -1} }
+
+// AtMost requires n or fewer matching requests.
+func AtMost(n int) Count { return Count{min: 0, max: n} }
+
+// Never requires that no request matches.
+func Never() Count { return Times(0) }
+
+// Satisfied checks if the given number of requests satisfies the Count constraint.
+func (c Count) Satisfied(n int) bool {
+	return n >= c.min && (c.max < 0 || n <= c.max)
+}
+
+func (c Count) String() string {
+	switch {
+	case c.max < 0:
+		return fmt.Sprintf("at least %d times", c.min)
+	case c.min == c.max:
+		return fmt.Sprintf("exactly %d times", c.min)
+	case c.min == 0:
+		return fmt.Sprintf("at most %d times", c.max)
+	default:
+		return fmt.Sprintf("between %d and %d times", c.min, c.max)
+	}
+}
+
+// Verify checks the received requests history against the expectations of the given MockBuilder,
+// regardless of which mock served each request, and reports an error if the number of matching requests does not
+// satisfy the Count constraint. When no Count is provided, AtLeast(1) is used.
+// Only the requests retained by the request journal are considered. The MockBuilder Reply is ignored.
+//
+// Usage:
+//
+//	m.Verify(t, mocha.Get(expect.URLPath("/test")).Header("x-id", expect.ToEqual("1")), mocha.Times(2))
+func (m *Mocha) Verify(t T, b *MockBuilder, count ...Count) bool {
+	t.Helper()
+
+	c := AtLeast(1)
+	if len(count) > 0 {
+		c = count[0]
+	}
+
+	mock := b.Build()
+	entries := m.journal.FetchAll()
+	matched := 0
+
+	for _, entry := range entries {
+		args := expect.Args{RequestInfo: entry.RequestInfo(), Params: m.params}
+
+		result, err := mock.matches(args, mock.Expectations)
+		if err != nil {
+			t.Logf("\nerror verifying request %s %s. error=%v", entry.Request.Method, entry.Request.URL, err)
+			continue
+		}
+
+		if result.IsMatch {
+			matched++
+		}
+	}
+
+	if c.Satisfied(matched) {
+		return true
+	}
+
+	received := strings.Builder{}
+	for _, entry := range entries {
+		received.WriteString(fmt.Sprintf("	%s %s\n", entry.Request.Method, entry.Request.URL))
+	}
+
+	t.Errorf("\nexpected matching requests to be received %s. got %d.\nreceived requests:\n%s", c, matched, received.String())
+
+	return false
+}
@@ -0,0 +1,86 @@
+package mocha
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitorsalgado/mocha/v3/expect"
+	"github.com/vitorsalgado/mocha/v3/internal/testmocks"
+	"github.com/vitorsalgado/mocha/v3/internal/testutil"
+	"github.com/vitorsalgado/mocha/v3/reply"
+)
+
+func TestCount(t *testing.T) {
+	testCases := []struct {
+		count    Count
+		n        int
+		expected bool
+		desc     string
+	}{
+		{Times(2), 2, true, "exactly 2 times"},
+		{Times(2), 1, false, "exactly 2 times"},
+		{Times(2), 3, false, "exactly 2 times"},
+		{AtLeast(2), 3, true, "at least 2 times"},
+		{AtLeast(2), 1, false, "at least 2 times"},
+		{AtMost(2), 0, true, "at most 2 times"},
+		{AtMost(2), 3, false, "at most 2 times"},
+		{Never(), 0, true, "exactly 0 times"},
+		{Never(), 1, false, "exactly 0 times"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, tc.count.Satisfied(tc.n))
+		assert.Equal(t, tc.desc, tc.count.String())
+	}
+}
+
+func TestMocha_Verify(t *testing.T) {
+	m := New(t)
+	m.Start()
+
+	m.AddMocks(Get(expect.URLPath("/test")).Reply(reply.OK()))
+
+	for i := 0; i < 2; i++ {
+		res, err := testutil.Get(m.URL()+"/test").Header("x-id", "1").Do()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+	}
+
+	// unmatched requests are verified as well
+	res, err := testutil.Get(m.URL() + "/unknown").Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusTeapot, res.StatusCode)
+
+	t.Run("should pass when count constraint is satisfied", func(t *testing.T) {
+		fakeT := testmocks.NewFakeNotifier()
+
+		assert.True(t, m.Verify(fakeT, Get(expect.URLPath("/test")).Header("x-id", expect.ToEqual("1")), Times(2)))
+		assert.True(t, m.Verify(fakeT, Get(expect.URLPath("/test")), AtLeast(1)))
+		assert.True(t, m.Verify(fakeT, Get(expect.URLPath("/test")), AtMost(2)))
+		assert.True(t, m.Verify(fakeT, Get(expect.URLPath("/unknown"))))
+		assert.True(t, m.Verify(fakeT, Post(expect.URLPath("/test")), Never()))
+
+		fakeT.AssertNotCalled(t, "Errorf")
+	})
+
+	t.Run("should fail when count constraint is not satisfied", func(t *testing.T) {
+		fakeT := testmocks.NewFakeNotifier()
+
+		assert.False(t, m.Verify(fakeT, Get(expect.URLPath("/test")), Times(1)))
+		assert.False(t, m.Verify(fakeT, Get(expect.URLPath("/test")), AtMost(1)))
+		assert.False(t, m.Verify(fakeT, Get(expect.URLPath("/test")).Header("x-id", expect.ToEqual("2"))))
+		assert.False(t, m.Verify(fakeT, Get(expect.URLPath("/unknown")), Never()))
+
+		fakeT.AssertNumberOfCalls(t, "Errorf", 4)
+	})
+}